@@ -0,0 +1,177 @@
+package rdd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dopsilva/rdd/builder"
+	"github.com/dopsilva/rdd/schema"
+)
+
+// Where cria a condição da coluna para as consultas Count, Exists, First, ...
+func Where(column, operator string, values ...any) builder.Condition {
+	return builder.Condition{Column: column, Operator: operator, Values: values}
+}
+
+// Count retorna a quantidade de registros da entidade T que atendem às condições
+func Count[T any](db Database, where ...builder.Condition) (int64, error) {
+	var count int64
+	err := aggregateRow[T](db, &builder.SelectOptions{
+		Aggregates: []builder.Aggregate{{Function: "count"}},
+		Where:      where,
+	}, &count)
+	return count, err
+}
+
+// Exists verifica se existe algum registro da entidade T que atenda às condições
+func Exists[T any](db Database, where ...builder.Condition) (bool, error) {
+	s := schemaOf[T]()
+
+	column, ok := s.PrimaryKey()
+	if !ok {
+		for k := range s.Fields {
+			if column == "" || k < column {
+				column = k
+			}
+		}
+	}
+
+	var v any
+	err := aggregateRow[T](db, &builder.SelectOptions{Columns: []string{column}, Where: where, Limit: 1}, &v)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// First retorna o primeiro registro da entidade T que atenda às condições, na ordem
+// informada. Retorna ErrNotFound se não houver registros. Importante que após o uso,
+// a entidade seja fechada com Close.
+func First[T any](db Database, orderBy []string, where ...builder.Condition) (*T, error) {
+	s := schemaOf[T]()
+
+	q, args, err := db.Builder().Select(*s, &builder.SelectOptions{
+		Where:   where,
+		OrderBy: orderBy,
+		Limit:   1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := Select[T](db, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	if r.Empty() {
+		return nil, ErrNotFound
+	}
+	return r[0], nil
+}
+
+// Sum retorna a soma da coluna da entidade T. Sem registros retorna o valor zero de V.
+func Sum[T, V any](db Database, column string, where ...builder.Condition) (V, error) {
+	return aggregate[T, V](db, "sum", column, where)
+}
+
+// Min retorna o menor valor da coluna da entidade T. Sem registros retorna o valor zero de V.
+func Min[T, V any](db Database, column string, where ...builder.Condition) (V, error) {
+	return aggregate[T, V](db, "min", column, where)
+}
+
+// Max retorna o maior valor da coluna da entidade T. Sem registros retorna o valor zero de V.
+func Max[T, V any](db Database, column string, where ...builder.Condition) (V, error) {
+	return aggregate[T, V](db, "max", column, where)
+}
+
+// Avg retorna a média da coluna da entidade T. Sem registros retorna o valor zero de V.
+func Avg[T, V any](db Database, column string, where ...builder.Condition) (V, error) {
+	return aggregate[T, V](db, "avg", column, where)
+}
+
+func aggregate[T, V any](db Database, function, column string, where []builder.Condition) (V, error) {
+	var v sql.Null[V]
+
+	s := schemaOf[T]()
+	f, ok := s.Fields[column]
+	if !ok {
+		return v.V, fmt.Errorf("rdd: column %s not defined on %s", column, entityName[T]())
+	}
+	if textDecimal(db, f) {
+		return v.V, fmt.Errorf("rdd: %s is not supported on decimal column %s, stored as text on %s", function, column, db.Engine())
+	}
+
+	err := aggregateRow[T](db, &builder.SelectOptions{
+		Aggregates: []builder.Aggregate{{Function: function, Column: column}},
+		Where:      where,
+	}, &v)
+	return v.V, err
+}
+
+// aggregateRow executa o select na tabela da entidade T e lê a linha retornada
+func aggregateRow[T any](db Database, options *builder.SelectOptions, dest ...any) (err error) {
+	ctx, done := startOperation(context.Background(), db, entityName[T](), "aggregate")
+	defer done(&err)
+
+	s := schemaOf[T]()
+
+	q, args, err := db.Builder().Select(*s, options)
+	if err != nil {
+		return err
+	}
+
+	if err := queryRowContext(ctx, db, newCall[T](q, args)).Scan(dest...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// GroupBy agrupa os registros da entidade T pelas colunas e retorna as colunas e as
+// agregações em R, ordenados pelas colunas do agrupamento. Os campos de R são associados
+// às colunas e aos aliases das agregações como no Query.
+func GroupBy[T, R any](db Database, columns []string, aggregates []builder.Aggregate, where ...builder.Condition) (_ []R, err error) {
+	ctx, done := startOperation(context.Background(), db, entityName[T](), "group-by")
+	defer done(&err)
+
+	s := schemaOf[T]()
+	for _, c := range columns {
+		if _, ok := s.Fields[c]; !ok {
+			return nil, fmt.Errorf("rdd: column %s not defined on %s", c, entityName[T]())
+		}
+	}
+	for _, a := range aggregates {
+		if f, ok := s.Fields[a.Column]; ok && a.Function != "count" && textDecimal(db, f) {
+			return nil, fmt.Errorf("rdd: %s is not supported on decimal column %s, stored as text on %s", a.Function, a.Column, db.Engine())
+		}
+	}
+
+	q, args, err := db.Builder().Select(*s, &builder.SelectOptions{
+		Columns:    columns,
+		Aggregates: aggregates,
+		Where:      where,
+		GroupBy:    columns,
+		OrderBy:    columns,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := queryContext(ctx, db, newCall[T](q, args))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRows[R](rows)
+}
+
+// textDecimal verifica se a coluna é um decimal armazenado como texto pela engine. Nestas
+// colunas as agregações e comparações do banco de dados não são numéricas.
+func textDecimal(db Database, f schema.Field) bool {
+	return f.FieldType == "Decimal" && db.Engine() == SQLite
+}
@@ -0,0 +1,100 @@
+package rdd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dopsilva/rdd/builder"
+	"github.com/dopsilva/rdd/engine"
+)
+
+func TestAggregates(t *testing.T) {
+	db, err := Connect(engine.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.CreateTable(schemaOf[Cidade](), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// sem registros as agregações retornam o valor zero
+	if sum, err := Sum[Cidade, int64](db, "populacao"); err != nil || sum != 0 {
+		t.Fatalf("esperado 0 obtido %d (%v)", sum, err)
+	}
+	if _, err := First[Cidade](db, nil); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("esperado %v obtido %v", ErrNotFound, err)
+	}
+
+	for _, q := range []string{
+		"insert into cidades (id, nome, populacao) values (1, 'Curitiba', 300)",
+		"insert into cidades (id, nome, populacao) values (2, 'Londrina', 100)",
+		"insert into cidades (id, nome, populacao) values (3, 'Curitiba', 200)",
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n, err := Count[Cidade](db); err != nil || n != 3 {
+		t.Fatalf("esperado 3 obtido %d (%v)", n, err)
+	}
+	if n, err := Count[Cidade](db, Where("nome", "=", "Curitiba")); err != nil || n != 2 {
+		t.Fatalf("esperado 2 obtido %d (%v)", n, err)
+	}
+
+	if ok, err := Exists[Cidade](db, Where("nome", "=", "Londrina")); err != nil || !ok {
+		t.Fatalf("esperado existente (%v)", err)
+	}
+	if ok, err := Exists[Cidade](db, Where("nome", "=", "Maringá")); err != nil || ok {
+		t.Fatalf("esperado inexistente (%v)", err)
+	}
+
+	c, err := First[Cidade](db, []string{"populacao desc"}, Where("nome", "=", "Curitiba"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ID.Get() != 1 {
+		t.Fatalf("esperado 1 obtido %d", c.ID.Get())
+	}
+	c.Close()
+
+	if sum, err := Sum[Cidade, int64](db, "populacao"); err != nil || sum != 600 {
+		t.Fatalf("esperado 600 obtido %d (%v)", sum, err)
+	}
+	if menor, err := Min[Cidade, int64](db, "populacao"); err != nil || menor != 100 {
+		t.Fatalf("esperado 100 obtido %d (%v)", menor, err)
+	}
+	if maior, err := Max[Cidade, string](db, "nome"); err != nil || maior != "Londrina" {
+		t.Fatalf("esperado Londrina obtido %s (%v)", maior, err)
+	}
+	if avg, err := Avg[Cidade, float64](db, "populacao", Where("nome", "=", "Curitiba")); err != nil || avg != 250 {
+		t.Fatalf("esperado 250 obtido %f (%v)", avg, err)
+	}
+	if _, err := Sum[Cidade, int64](db, "area"); err == nil {
+		t.Fatal("esperado erro com coluna inexistente")
+	}
+
+	type resumo struct {
+		Nome      string  `rdd-column:"nome"`
+		Cidades   int64   `rdd-column:"cidades"`
+		Populacao float64 `rdd-column:"populacao"`
+	}
+
+	r, err := GroupBy[Cidade, resumo](db, []string{"nome"}, []builder.Aggregate{
+		{Function: "count", Alias: "cidades"},
+		{Function: "sum", Column: "populacao", Alias: "populacao"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r) != 2 || r[0] != (resumo{"Curitiba", 2, 500}) || r[1] != (resumo{"Londrina", 1, 100}) {
+		t.Fatalf("agrupamento inesperado %v", r)
+	}
+
+	// as colunas devem ter um campo na estrutura
+	if _, err := GroupBy[Cidade, resumo](db, []string{"nome"}, []builder.Aggregate{{Function: "max", Column: "id", Alias: "maior"}}); err == nil {
+		t.Fatal("esperado erro com coluna sem campo")
+	}
+}
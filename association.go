@@ -0,0 +1,437 @@
+package rdd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dopsilva/rdd/builder"
+	"github.com/dopsilva/rdd/field"
+	"github.com/dopsilva/rdd/schema"
+)
+
+// relationBinder é implementada pelos relacionamentos que dependem da entidade proprietária
+type relationBinder interface {
+	bind(owner relationKeyer, table *schema.Table, rel schema.Relation)
+}
+
+// relationKeyer retorna o valor da coluna da entidade proprietária
+type relationKeyer interface {
+	relationKey(column string) any
+}
+
+// ManyToMany é o relacionamento com outra entidade através de uma tabela de associação.
+// Ex.: Tags rdd.ManyToMany[Tag] `rdd-many-to-many:"posts_tags" rdd-join-columns:"post_id,tag_id"`
+//
+// Se a tabela de associação não for declarada como entidade, ela é criada no CreateTable.
+// As entidades relacionadas não retornam ao pool no Close da entidade.
+type ManyToMany[T any] struct {
+	owner     relationKeyer
+	table     *schema.Table
+	rel       schema.Relation
+	values    Resultset[T]
+	loaded    bool
+	old       Resultset[T]
+	oldLoaded bool
+}
+
+// Get retorna as entidades associadas
+func (r *ManyToMany[T]) Get() Resultset[T] {
+	return r.values
+}
+
+// Loaded verifica se o relacionamento foi carregado pelo Include ou Load
+func (r *ManyToMany[T]) Loaded() bool {
+	return r.loaded
+}
+
+// Load carrega as entidades associadas à entidade proprietária
+func (r *ManyToMany[T]) Load(db Database) error {
+	key, err := r.ownerKey()
+	if err != nil {
+		return err
+	}
+	return r.include(db, r.rel, []relationOwner{{key: key, field: r}})
+}
+
+// Add associa as entidades à entidade proprietária
+func (r *ManyToMany[T]) Add(ctx context.Context, db Database, items ...*T) error {
+	key, err := r.ownerKey()
+	if err != nil {
+		return err
+	}
+
+	join, err := joinSchema(r.table, r.rel)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		tk, err := r.targetKey(item)
+		if err != nil {
+			return err
+		}
+
+		q, args, _, err := db.Builder().Insert(*join, r.joinFields(join, key, tk))
+		if err != nil {
+			return err
+		}
+		if _, err := execContext(ctx, db, &Call{Query: q, Args: args, Schema: join}); err != nil {
+			return err
+		}
+
+		if r.loaded {
+			r.values = append(r.values, item)
+		}
+	}
+
+	r.store(db)
+
+	return nil
+}
+
+// Remove desfaz a associação das entidades com a entidade proprietária
+func (r *ManyToMany[T]) Remove(ctx context.Context, db Database, items ...*T) error {
+	key, err := r.ownerKey()
+	if err != nil {
+		return err
+	}
+
+	join, err := joinSchema(r.table, r.rel)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		tk, err := r.targetKey(item)
+		if err != nil {
+			return err
+		}
+
+		q, args := db.Builder().Delete(*join, r.joinFields(join, key, tk))
+		if _, err := execContext(ctx, db, &Call{Query: q, Args: args, Schema: join}); err != nil {
+			return err
+		}
+
+		if r.loaded {
+			values := make(Resultset[T], 0, len(r.values))
+			for _, v := range r.values {
+				if k, _ := r.targetKey(v); k != tk {
+					values = append(values, v)
+				}
+			}
+			r.values = values
+		}
+	}
+
+	r.store(db)
+
+	return nil
+}
+
+// Replace substitui as entidades associadas à entidade proprietária.
+// Fora de uma transação, a substituição é executada em uma nova transação.
+func (r *ManyToMany[T]) Replace(ctx context.Context, db Database, items ...*T) error {
+	if !db.WithinTransaction() {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if err := r.Replace(ctx, tx, items...); err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+		return tx.Commit(ctx)
+	}
+
+	key, err := r.ownerKey()
+	if err != nil {
+		return err
+	}
+
+	join, err := joinSchema(r.table, r.rel)
+	if err != nil {
+		return err
+	}
+
+	// remove todas as associações da entidade proprietária
+	if err := r.removeAll(ctx, db, join, key); err != nil {
+		return err
+	}
+
+	r.values, r.loaded = make(Resultset[T], 0, len(items)), true
+
+	return r.Add(ctx, db, items...)
+}
+
+// removeAll remove todas as associações da entidade proprietária
+func (r *ManyToMany[T]) removeAll(ctx context.Context, db Database, join *schema.Table, key any) error {
+	owner := join.Fields[r.rel.Column]
+	owner.PrimaryKey, owner.UniqueKey = true, false
+	q, args := db.Builder().Delete(*join, []field.FieldInstance{{Schema: owner, Addr: key}})
+	_, err := execContext(ctx, db, &Call{Query: q, Args: args, Schema: join})
+	return err
+}
+
+// Freeze confirma as associações em memória
+func (r *ManyToMany[T]) Freeze() {
+	r.old = append(Resultset[T](nil), r.values...)
+	r.oldLoaded = r.loaded
+}
+
+// Restore retorna as associações em memória ao estado confirmado
+func (r *ManyToMany[T]) Restore() {
+	r.values = append(Resultset[T](nil), r.old...)
+	r.loaded = r.oldLoaded
+}
+
+func (r *ManyToMany[T]) relationKind() schema.RelationKind { return schema.ManyToMany }
+func (r *ManyToMany[T]) relationEntity() string            { return entityName[T]() }
+
+func (r *ManyToMany[T]) bind(owner relationKeyer, table *schema.Table, rel schema.Relation) {
+	r.owner, r.table, r.rel = owner, table, rel
+}
+
+func (r *ManyToMany[T]) clear() {
+	r.values, r.loaded = nil, false
+	r.old, r.oldLoaded = nil, false
+}
+
+func (r *ManyToMany[T]) cascadeSave(ctx context.Context, db Database, rel schema.Relation, key any) (any, error) {
+	return nil, nil
+}
+
+func (r *ManyToMany[T]) cascadeRemove(ctx context.Context, db Database, rel schema.Relation, key any) error {
+	join, err := joinSchema(r.table, rel)
+	if err != nil {
+		return err
+	}
+	if err := r.removeAll(ctx, db, join, key); err != nil {
+		return err
+	}
+	if r.loaded {
+		r.values = make(Resultset[T], 0)
+		r.store(db)
+	}
+	return nil
+}
+
+func (r *ManyToMany[T]) include(db Database, rel schema.Relation, owners []relationOwner) error {
+	join, err := joinSchema(r.table, rel)
+	if err != nil {
+		return err
+	}
+
+	pk, ok := schemaOf[T]().PrimaryKey()
+	if !ok {
+		return fmt.Errorf("rdd: relation %s: %s has no single primary key", rel.Name, rel.Entity)
+	}
+
+	// lê as associações dos proprietários
+	associations := make(map[any][]any)
+	targets := make([]any, 0)
+	seen := make(map[any]bool)
+
+	read := func(keys []any) error {
+		q, args, err := db.Builder().Select(*join, &builder.SelectOptions{
+			Columns: []string{rel.Column, rel.JoinColumn},
+			Where:   []builder.Condition{{Column: rel.Column, Operator: "in", Values: keys}},
+		})
+		if err != nil {
+			return err
+		}
+
+		rows, err := queryContext(context.Background(), db, &Call{Query: q, Args: args, Schema: join})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var key, tk any
+			if err := rows.Scan(&key, &tk); err != nil {
+				return err
+			}
+			key, tk = relationKey(key), relationKey(tk)
+			associations[key] = append(associations[key], tk)
+			if !seen[tk] {
+				seen[tk] = true
+				targets = append(targets, tk)
+			}
+		}
+		return rows.Err()
+	}
+
+	for _, keys := range keyBatches(ownerKeys(owners)) {
+		if err := read(keys); err != nil {
+			return err
+		}
+	}
+
+	res, err := selectRelated[T](db, rel, pk, targets)
+	if err != nil {
+		return err
+	}
+
+	related := make(map[any]*T, len(res))
+	for _, e := range res {
+		related[relationKeyOf(e, pk)] = e
+	}
+
+	for _, o := range owners {
+		f := o.field.(*ManyToMany[T])
+		f.values, f.loaded = make(Resultset[T], 0), true
+		for _, tk := range associations[o.key] {
+			if e, ok := related[tk]; ok {
+				f.values = append(f.values, e)
+			}
+		}
+		f.Freeze()
+	}
+
+	return nil
+}
+
+// store registra as associações na transação ou as confirma
+func (r *ManyToMany[T]) store(db Database) {
+	if db.WithinTransaction() {
+		db.StoreWorkarea(r)
+	} else {
+		r.Freeze()
+	}
+}
+
+func (r *ManyToMany[T]) ownerKey() (any, error) {
+	if r.owner == nil {
+		return nil, fmt.Errorf("rdd: relation %s not bound to an entity", r.rel.Name)
+	}
+	column, err := ownerColumn(r.table, r.rel)
+	if err != nil {
+		return nil, err
+	}
+	key := r.owner.relationKey(column)
+	if key == nil {
+		return nil, fmt.Errorf("rdd: relation %s: %s has no value", r.rel.Name, column)
+	}
+	return key, nil
+}
+
+func (r *ManyToMany[T]) targetKey(e *T) (any, error) {
+	pk, ok := schemaOf[T]().PrimaryKey()
+	if !ok {
+		return nil, fmt.Errorf("rdd: relation %s: %s has no single primary key", r.rel.Name, r.rel.Entity)
+	}
+	key := relationKeyOf(e, pk)
+	if key == nil {
+		return nil, fmt.Errorf("rdd: relation %s: %s has no value", r.rel.Name, pk)
+	}
+	return key, nil
+}
+
+// joinFields retorna as colunas da associação identificadas como primary key
+func (r *ManyToMany[T]) joinFields(join *schema.Table, owner, target any) []field.FieldInstance {
+	of, tf := join.Fields[r.rel.Column], join.Fields[r.rel.JoinColumn]
+	of.PrimaryKey, of.UniqueKey = true, false
+	tf.PrimaryKey, tf.UniqueKey = true, false
+	return []field.FieldInstance{{Schema: of, Addr: owner}, {Schema: tf, Addr: target}}
+}
+
+// parseJoinTags lê o relacionamento ManyToMany das tags do campo
+func parseJoinTags(owner, name string, tag reflect.StructTag, rel schema.Relation) schema.Relation {
+	tv, ok := tag.Lookup("rdd-many-to-many")
+	if !ok || strings.TrimSpace(tv) == "" {
+		panic(fmt.Errorf("workarea: rdd-many-to-many not defined on relation %s", name))
+	}
+	rel.JoinTable = tv
+
+	rel.Column = strings.ToLower(owner) + "_id"
+	rel.JoinColumn = strings.ToLower(rel.Entity) + "_id"
+	if tv, ok := tag.Lookup("rdd-join-columns"); ok {
+		columns := strings.Split(tv, ",")
+		if len(columns) != 2 {
+			panic(fmt.Errorf("workarea: invalid rdd-join-columns on relation %s", name))
+		}
+		rel.Column, rel.JoinColumn = strings.TrimSpace(columns[0]), strings.TrimSpace(columns[1])
+	}
+
+	if tv, ok := tag.Lookup("rdd-references"); ok {
+		rel.References = tv
+	}
+
+	return rel
+}
+
+// joinSchema retorna o schema da tabela de associação. Se a tabela não foi declarada
+// como entidade, o schema é criado a partir das chaves das duas entidades.
+func joinSchema(owner *schema.Table, rel schema.Relation) (*schema.Table, error) {
+	if t, ok := declaredTable(rel.JoinTable); ok {
+		return t, nil
+	}
+
+	target, ok := relatedSchema(owner, rel)
+	if !ok {
+		return nil, fmt.Errorf("rdd: relation %s: entity %s not registered", rel.Name, rel.Entity)
+	}
+
+	oc, err := ownerColumn(owner, rel)
+	if err != nil {
+		return nil, err
+	}
+	tc, ok := target.PrimaryKey()
+	if !ok {
+		return nil, fmt.Errorf("rdd: relation %s: %s has no single primary key", rel.Name, rel.Entity)
+	}
+
+	return &schema.Table{
+		Name: rel.JoinTable,
+		Fields: map[string]schema.Field{
+			rel.Column:     {Name: rel.Column, PrimaryKey: true, FieldType: owner.Fields[oc].FieldType},
+			rel.JoinColumn: {Name: rel.JoinColumn, PrimaryKey: true, FieldType: target.Fields[tc].FieldType},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Fields: []string{rel.Column}, Reference: owner.Name},
+			{Fields: []string{rel.JoinColumn}, Reference: target.Name},
+		},
+	}, nil
+}
+
+// declaredTable retorna o schema da entidade registrada com o nome da tabela
+func declaredTable(name string) (*schema.Table, bool) {
+	for _, t := range registeredSchemas {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// createJoinTables cria as tabelas de associação não declaradas da tabela
+func createJoinTables(db Database, table *schema.Table) error {
+	for _, rel := range table.Relations {
+		if rel.Kind != schema.ManyToMany {
+			continue
+		}
+		if _, ok := declaredTable(rel.JoinTable); ok {
+			continue
+		}
+
+		join, err := joinSchema(table, rel)
+		if err != nil {
+			return err
+		}
+
+		q, err := db.Builder().CreateTable(join, &builder.CreateTableOptions{IfNotExists: true})
+		if err != nil {
+			return err
+		}
+		if _, err := db.Exec(q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package rdd
+
+import (
+	"testing"
+
+	"github.com/dopsilva/rdd/engine"
+	"github.com/dopsilva/rdd/field"
+	"github.com/dopsilva/rdd/schema"
+)
+
+type Produto struct {
+	Workarea[Produto] `rdd-table:"produtos"`
+
+	ID   field.Field[int64]  `rdd-column:"id" rdd-primary-key:"true"`
+	Nome field.Field[string] `rdd-column:"nome"`
+
+	Categorias ManyToMany[Categoria] `rdd-many-to-many:"produtos_categorias"`
+}
+
+type Categoria struct {
+	Workarea[Categoria] `rdd-table:"categorias"`
+
+	ID   field.Field[string] `rdd-column:"id" rdd-primary-key:"true"`
+	Nome field.Field[string] `rdd-column:"nome"`
+}
+
+func init() {
+	Register[Produto]()
+	Register[Categoria]()
+}
+
+func TestManyToMany(t *testing.T) {
+	db, err := Connect(engine.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, e := range []*schema.Table{schemaOf[Categoria](), schemaOf[Produto]()} {
+		if err := db.CreateTable(e, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, q := range []string{
+		"insert into categorias (id, nome) values ('a', 'Alimentos'), ('b', 'Bebidas'), ('c', 'Congelados')",
+		"insert into produtos (id, nome) values (1, 'Suco'), (2, 'Pizza')",
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	categorias, err := Select[Categoria](db, "select id, nome from categorias order by id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer categorias.Close()
+
+	produtos, err := Select[Produto](db, "select id, nome from produtos order by id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer produtos.Close()
+
+	suco, pizza := produtos[0], produtos[1]
+
+	if err := suco.Categorias.Add(testContext, db, categorias[0], categorias[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := pizza.Categorias.Replace(testContext, db, categorias[0], categorias[2]); err != nil {
+		t.Fatal(err)
+	}
+	if len(pizza.Categorias.Get()) != 2 {
+		t.Fatalf("esperado 2 categorias obtido %d", len(pizza.Categorias.Get()))
+	}
+
+	// associação duplicada
+	if err := suco.Categorias.Add(testContext, db, categorias[0]); !db.IsDuplicatedError(err) {
+		t.Fatalf("esperado erro de duplicidade obtido %v", err)
+	}
+
+	if err := produtos.Include(db, "Categorias"); err != nil {
+		t.Fatal(err)
+	}
+	if len(suco.Categorias.Get()) != 2 || suco.Categorias.Get()[1].Nome.Get() != "Bebidas" {
+		t.Fatalf("categorias inesperadas %v", suco.Categorias.Get())
+	}
+
+	// alterações desfeitas no rollback
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := suco.Categorias.Remove(testContext, tx, categorias[1]); err != nil {
+		t.Fatal(err)
+	}
+	if len(suco.Categorias.Get()) != 1 {
+		t.Fatal("esperado 1 categoria")
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if len(suco.Categorias.Get()) != 2 {
+		t.Fatal("esperado categorias restauradas após o rollback")
+	}
+
+	// alterações desfeitas no rollback do savepoint
+	tx, err = db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sp, err := tx.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := suco.Categorias.Remove(testContext, sp, categorias[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := sp.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if len(suco.Categorias.Get()) != 2 {
+		t.Fatal("esperado categorias restauradas após o rollback do savepoint")
+	}
+	if err := tx.Commit(testContext); err != nil {
+		t.Fatal(err)
+	}
+	if len(suco.Categorias.Get()) != 2 {
+		t.Fatal("esperado categorias mantidas após o commit")
+	}
+
+	// alterações confirmadas no commit
+	tx, err = db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pizza.Categorias.Replace(testContext, tx, categorias[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(testContext); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pizza.Categorias.Load(db); err != nil {
+		t.Fatal(err)
+	}
+	if len(pizza.Categorias.Get()) != 1 || pizza.Categorias.Get()[0].ID.Get() != "b" {
+		t.Fatalf("categorias inesperadas %v", pizza.Categorias.Get())
+	}
+
+	var n int
+	if err := db.QueryRow("select count(*) from produtos_categorias").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("esperado 3 associações obtido %d", n)
+	}
+}
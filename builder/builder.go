@@ -1,6 +1,10 @@
 package builder
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/dopsilva/rdd/engine"
 	"github.com/dopsilva/rdd/field"
 	"github.com/dopsilva/rdd/schema"
@@ -11,11 +15,117 @@ type CreateTableOptions struct {
 	DropIfExists bool
 }
 
+// Condition é a condição da clausula where do Select.
+// Os operadores aceitos são =, <>, <, <=, >, >=, in, is null e is not null.
+type Condition struct {
+	Column   string
+	Operator string
+	Values   []any
+	// Any são grupos alternativos de condições: (a and b) or (c). Se informado, Column,
+	// Operator e Values são ignorados.
+	Any [][]Condition
+}
+
+// LockMode é o bloqueio dos registros retornados pelo Select
+type LockMode int
+
+const (
+	LockNone LockMode = iota
+	LockForUpdate
+	LockForShare
+)
+
+// Lock é o bloqueio pessimista dos registros retornados pelo Select
+type Lock struct {
+	Mode LockMode
+	// NoWait retorna erro se algum registro já estiver bloqueado
+	NoWait bool
+	// SkipLocked ignora os registros já bloqueados
+	SkipLocked bool
+}
+
+// Validate verifica se as opções do bloqueio são compatíveis
+func (l Lock) Validate() error {
+	if l.Mode == LockNone && (l.NoWait || l.SkipLocked) {
+		return errors.New("builder: nowait and skip locked require a lock mode")
+	}
+	if l.NoWait && l.SkipLocked {
+		return errors.New("builder: nowait and skip locked are mutually exclusive")
+	}
+	return nil
+}
+
+// Clause retorna a clausula do bloqueio na sintaxe da engine, adicionada ao final do select.
+// No SQLite não há clausula, pois a transação bloqueia o banco de dados na primeira gravação.
+func (l Lock) Clause(e engine.Engine) (string, error) {
+	if err := l.Validate(); err != nil {
+		return "", err
+	}
+	if l.Mode == LockNone {
+		return "", nil
+	}
+
+	switch e {
+	case engine.SQLite:
+		return "", nil
+	case engine.Cockroach:
+		clause := " for update"
+		if l.Mode == LockForShare {
+			clause = " for share"
+		}
+		switch {
+		case l.NoWait:
+			clause += " nowait"
+		case l.SkipLocked:
+			clause += " skip locked"
+		}
+		return clause, nil
+	}
+	return "", fmt.Errorf("builder: row locking not supported on engine %d", e)
+}
+
+// Aggregate é a função de agregação retornada pelo Select. As funções aceitas são
+// count, sum, min, max e avg. Sem a coluna, o count conta os registros.
+type Aggregate struct {
+	Function string
+	Column   string
+	Alias    string
+}
+
+type SelectOptions struct {
+	// Columns são as colunas retornadas. Se não informado e sem Aggregates são retornadas
+	// todas as colunas da tabela.
+	Columns    []string
+	Aggregates []Aggregate
+	Where      []Condition
+	GroupBy    []string
+	// OrderBy são as colunas da ordenação, opcionalmente seguidas de asc ou desc
+	OrderBy []string
+	Limit   int
+	Offset  int
+	Lock    Lock
+}
+
+// ParseOrder separa a coluna e a direção (asc ou desc) da ordenação
+func ParseOrder(order string) (column string, desc bool, err error) {
+	parts := strings.Fields(order)
+	switch {
+	case len(parts) == 1:
+		return parts[0], false, nil
+	case len(parts) == 2 && strings.EqualFold(parts[1], "asc"):
+		return parts[0], false, nil
+	case len(parts) == 2 && strings.EqualFold(parts[1], "desc"):
+		return parts[0], true, nil
+	}
+	return "", false, fmt.Errorf("builder: invalid order %q", order)
+}
+
 type Builder interface {
 	CreateTable(*schema.Table, *CreateTableOptions) (string, error)
 	Insert(schema.Table, []field.FieldInstance) (string, []any, []any, error)
 	Update(schema.Table, []field.FieldInstance) (string, []any, []any, error)
 	Delete(schema.Table, []field.FieldInstance) (string, []any)
+	Select(schema.Table, *SelectOptions) (string, []any, error)
 
 	QuotedIdentifier(i string) string
 	QuotedValue(v any) string
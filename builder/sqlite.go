@@ -2,8 +2,10 @@ package builder
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/dopsilva/rdd/engine"
 	"github.com/dopsilva/rdd/field"
 	"github.com/dopsilva/rdd/schema"
 )
@@ -38,7 +40,11 @@ func (e SQLite) CreateTable(table *schema.Table, options *CreateTableOptions) (s
 		if n > 0 {
 			b.WriteString(", ")
 		}
-		b.WriteString(e.createColumn(f))
+		c, err := e.createColumn(f)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(c)
 		if f.PrimaryKey {
 			pk = append(pk, f)
 		}
@@ -68,40 +74,51 @@ func (e SQLite) CreateTable(table *schema.Table, options *CreateTableOptions) (s
 		b.WriteString(", constraint " + cn + " unique (" + e.QuotedIdentifier(uk.Name) + ")")
 	}
 
-	if len(table.ForeignKeys) > 0 {
-		b.WriteString(",")
-		for _, c := range table.ForeignKeys {
-			cn := "fk"
-			cf := ""
-			for i, f := range c.Fields {
-				if i > 0 {
-					cf += ", "
-				}
-				cn += "_" + f
-				cf += e.QuotedIdentifier(f)
+	for _, c := range table.Checks {
+		b.WriteString(", constraint " + c.Name + " check (" + c.Expression + ")")
+	}
+
+	// restringe as colunas aos valores enumerados
+	for _, f := range enumFields(table) {
+		cn := "ck_" + table.Name + "_" + f.Name + "_enum"
+		b.WriteString(", constraint " + cn + " check (" + e.QuotedIdentifier(f.Name) + " in (")
+		for i, v := range f.Enum {
+			if i > 0 {
+				b.WriteString(", ")
 			}
-			b.WriteString(" constraint " + cn + " foreign key (" + cf + ") references " + e.QuotedIdentifier(c.Reference))
+			b.WriteString(enumLiteral(e, f, v))
 		}
+		b.WriteString("))")
+	}
+
+	for _, c := range table.ForeignKeys {
+		cn := "fk"
+		cf := ""
+		for i, f := range c.Fields {
+			if i > 0 {
+				cf += ", "
+			}
+			cn += "_" + f
+			cf += e.QuotedIdentifier(f)
+		}
+		b.WriteString(", constraint " + cn + " foreign key (" + cf + ") references " + e.QuotedIdentifier(c.Reference))
 	}
 
 	b.WriteString(");")
-	//fmt.Println(b.String())
 
 	return b.String(), nil
 
 }
 
-func (e SQLite) createColumn(f schema.Field) string {
+func (e SQLite) createColumn(f schema.Field) (string, error) {
 	var b strings.Builder
 
 	b.WriteString(e.QuotedIdentifier(f.Name))
 
-	//fmt.Println(f.fieldtype)
-
 	switch f.FieldType {
 	case "string", "NullString":
 		b.WriteString(" text")
-	case "int", "int64", "NullInt64":
+	case "int", "int64", "int32", "int16", "NullInt64", "NullInt32", "NullInt16":
 		b.WriteString(" integer")
 	case "bool", "NullBool":
 		b.WriteString(" integer")
@@ -109,6 +126,24 @@ func (e SQLite) createColumn(f schema.Field) string {
 		b.WriteString(" real")
 	case "Time", "NullTime":
 		b.WriteString(" text")
+	case "bytes":
+		b.WriteString(" blob")
+	case "Decimal":
+		// armazenado como texto para não perder precisão
+		b.WriteString(" text")
+	case "UUID", "JSON":
+		b.WriteString(" text")
+	default:
+		// tipos definidos pelo usuário
+		ct, ok := field.ColumnType(f.FieldType, engine.SQLite)
+		if !ok {
+			return "", fmt.Errorf("builder: unknown column type %s for %s", f.FieldType, f.Name)
+		}
+		b.WriteString(" " + ct)
+	}
+
+	if f.Generated != "" {
+		b.WriteString(" generated always as (" + f.Generated + ") stored")
 	}
 
 	if f.Nullable {
@@ -117,17 +152,51 @@ func (e SQLite) createColumn(f schema.Field) string {
 		b.WriteString(" not null")
 	}
 
-	if f.Default != "" {
-		b.WriteString(" default ")
-		switch f.Default {
-		case "new_uuid":
-			b.WriteString(e.DefaultRandomUUID())
-		case "now":
-			b.WriteString(e.DefaultCurrentTimestamp())
-		}
+	if f.Check != "" {
+		b.WriteString(" check (" + f.Check + ")")
+	}
+
+	if def, ok, err := e.defaultExpression(f); err != nil {
+		return "", err
+	} else if ok {
+		b.WriteString(" default " + def)
+	}
+
+	return b.String(), nil
+}
+
+// defaultExpression retorna a expressão do valor padrão da coluna.
+// A expressão da engine (rdd-default-sqlite) tem precedência sobre o rdd-default.
+func (e SQLite) defaultExpression(f schema.Field) (string, bool, error) {
+	if f.Generated != "" {
+		return "", false, nil
+	}
+	if raw, ok := f.Defaults[engine.SQLite]; ok {
+		return "(" + raw + ")", true, nil
+	}
+	if f.Default == "" {
+		return "", false, nil
+	}
+
+	d, err := schema.ParseDefault(f.Default)
+	if err != nil {
+		return "", false, fmt.Errorf("builder: column %s: %w", f.Name, err)
+	}
+	if !d.IsFunction() {
+		return e.QuotedValue(d.Literal), true, nil
 	}
 
-	return b.String()
+	switch d.Function {
+	case schema.DefaultNewUUID:
+		return e.DefaultRandomUUID(), true, nil
+	case schema.DefaultNow:
+		return e.DefaultCurrentTimestamp(), true, nil
+	case schema.DefaultCurrentDate:
+		return "current_date", true, nil
+	case schema.DefaultCurrentTime:
+		return "current_time", true, nil
+	}
+	return "", false, fmt.Errorf("builder: default function %s not supported by sqlite for %s", d.Function, f.Name)
 }
 
 func (e SQLite) Insert(table schema.Table, fields []field.FieldInstance) (string, []any, []any, error) {
@@ -140,7 +209,7 @@ func (e SQLite) Insert(table schema.Table, fields []field.FieldInstance) (string
 
 	n := 0
 	for _, f := range fields {
-		if f.Schema.AutoGenerated {
+		if f.Schema.Computed() {
 			retfields = append(retfields, f)
 			returning = append(returning, f.Addr)
 			continue
@@ -203,7 +272,7 @@ func (e SQLite) Update(schema schema.Table, fields []field.FieldInstance) (strin
 		if v.Schema.PrimaryKey {
 			pk = append(pk, v)
 		}
-		if v.Schema.AutoGenerated && !v.Schema.PrimaryKey {
+		if v.Schema.Computed() && !v.Schema.PrimaryKey {
 			retfields = append(retfields, v)
 			returning = append(returning, v.Addr)
 			continue
@@ -224,6 +293,18 @@ func (e SQLite) Update(schema schema.Table, fields []field.FieldInstance) (strin
 		i++
 	}
 
+	q.WriteString(" where ")
+
+	if where, wargs, ok := e.wherePrimaryKey(fields, len(arguments)); ok {
+		q.WriteString(where)
+		arguments = append(arguments, wargs...)
+	} else if where, wargs, ok = e.whereUniqueKey(fields, len(arguments)); ok {
+		q.WriteString(where)
+		arguments = append(arguments, wargs...)
+	} else {
+		panic("update: tabela sem primary ou unique key definido")
+	}
+
 	if len(retfields) > 0 {
 		q.WriteString(" returning ")
 
@@ -237,18 +318,6 @@ func (e SQLite) Update(schema schema.Table, fields []field.FieldInstance) (strin
 		}
 	}
 
-	q.WriteString(" where ")
-
-	if where, wargs, ok := e.wherePrimaryKey(fields, len(arguments)); ok {
-		q.WriteString(where)
-		arguments = append(arguments, wargs...)
-	} else if where, wargs, ok = e.whereUniqueKey(fields, len(arguments)); ok {
-		q.WriteString(where)
-		arguments = append(arguments, wargs...)
-	} else {
-		panic("update: tabela sem primary ou unique key definido")
-	}
-
 	q.WriteString(";")
 
 	return q.String(), arguments, returning, nil
@@ -274,6 +343,168 @@ func (e SQLite) Delete(schema schema.Table, fields []field.FieldInstance) (strin
 	return sb.String(), wargs
 }
 
+func (e SQLite) Select(table schema.Table, options *SelectOptions) (string, []any, error) {
+	var sb strings.Builder
+	opt := SelectOptions{}
+	args := make([]any, 0)
+
+	if options != nil {
+		opt = *options
+	}
+
+	// o SQLite não possui bloqueio de registros: a transação bloqueia o banco de dados
+	// na primeira gravação, portanto o bloqueio é apenas validado
+	lock, err := opt.Lock.Clause(engine.SQLite)
+	if err != nil {
+		return "", nil, err
+	}
+
+	columns := opt.Columns
+	if len(columns) == 0 && len(opt.Aggregates) == 0 {
+		columns = tableColumns(&table)
+	}
+
+	sb.WriteString("select ")
+	for i, c := range columns {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(e.QuotedIdentifier(c))
+	}
+	for i, a := range opt.Aggregates {
+		if i > 0 || len(columns) > 0 {
+			sb.WriteString(", ")
+		}
+
+		fn := strings.ToLower(a.Function)
+		switch {
+		case fn == "count" && a.Column == "":
+			sb.WriteString("count(*)")
+		case slices.Contains([]string{"count", "sum", "min", "max", "avg"}, fn) && a.Column != "":
+			sb.WriteString(fn + "(" + e.QuotedIdentifier(a.Column) + ")")
+		default:
+			return "", nil, fmt.Errorf("builder: invalid aggregate %s(%s)", a.Function, a.Column)
+		}
+		if a.Alias != "" {
+			sb.WriteString(" as " + e.QuotedIdentifier(a.Alias))
+		}
+	}
+	sb.WriteString(" from " + e.QuotedIdentifier(table.Name))
+
+	for i, c := range opt.Where {
+		if i == 0 {
+			sb.WriteString(" where ")
+		} else {
+			sb.WriteString(" and ")
+		}
+
+		cond, err := e.condition(c, &args)
+		if err != nil {
+			return "", nil, err
+		}
+		sb.WriteString(cond)
+	}
+
+	for i, c := range opt.GroupBy {
+		if i == 0 {
+			sb.WriteString(" group by ")
+		} else {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(e.QuotedIdentifier(c))
+	}
+
+	for i, c := range opt.OrderBy {
+		if i == 0 {
+			sb.WriteString(" order by ")
+		} else {
+			sb.WriteString(", ")
+		}
+
+		column, desc, err := ParseOrder(c)
+		if err != nil {
+			return "", nil, err
+		}
+		sb.WriteString(e.QuotedIdentifier(column))
+		if desc {
+			sb.WriteString(" desc")
+		}
+	}
+
+	if opt.Limit > 0 {
+		args = append(args, opt.Limit)
+		sb.WriteString(fmt.Sprintf(" limit $%d", len(args)))
+	}
+	if opt.Offset > 0 {
+		if opt.Limit <= 0 {
+			// o SQLite exige o limit com o offset
+			sb.WriteString(" limit -1")
+		}
+		args = append(args, opt.Offset)
+		sb.WriteString(fmt.Sprintf(" offset $%d", len(args)))
+	}
+	sb.WriteString(lock)
+
+	return sb.String(), args, nil
+}
+
+// condition cria a condição da clausula where do Select
+func (e SQLite) condition(c Condition, args *[]any) (string, error) {
+	if len(c.Any) > 0 {
+		var sb strings.Builder
+		sb.WriteString("(")
+		for i, group := range c.Any {
+			if i > 0 {
+				sb.WriteString(" or ")
+			}
+			sb.WriteString("(")
+			for j, gc := range group {
+				if j > 0 {
+					sb.WriteString(" and ")
+				}
+				cond, err := e.condition(gc, args)
+				if err != nil {
+					return "", err
+				}
+				sb.WriteString(cond)
+			}
+			sb.WriteString(")")
+		}
+		sb.WriteString(")")
+		return sb.String(), nil
+	}
+
+	column := e.QuotedIdentifier(c.Column)
+
+	switch op := strings.ToLower(c.Operator); op {
+	case "=", "<>", "<", "<=", ">", ">=":
+		if len(c.Values) != 1 {
+			return "", fmt.Errorf("builder: operator %s on %s expects one value", op, c.Column)
+		}
+		*args = append(*args, c.Values[0])
+		return column + " " + op + " " + fmt.Sprintf("$%d", len(*args)), nil
+	case "in":
+		if len(c.Values) == 0 {
+			// nenhum valor aceito
+			return "1 = 0", nil
+		}
+		var sb strings.Builder
+		sb.WriteString(column + " in (")
+		for j, v := range c.Values {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			*args = append(*args, v)
+			sb.WriteString(fmt.Sprintf("$%d", len(*args)))
+		}
+		sb.WriteString(")")
+		return sb.String(), nil
+	case "is null", "is not null":
+		return column + " " + op, nil
+	}
+	return "", fmt.Errorf("builder: unknown operator %s on %s", c.Operator, c.Column)
+}
+
 // wherePrimaryKey cria a condição para a clausula where baseada na primary key da tabela
 func (e SQLite) wherePrimaryKey(fields []field.FieldInstance, argsCount int) (string, []any, bool) {
 	var sb strings.Builder
@@ -289,6 +520,7 @@ func (e SQLite) wherePrimaryKey(fields []field.FieldInstance, argsCount int) (st
 			sb.WriteString(e.QuotedIdentifier(v.Schema.Name) + " = " + fmt.Sprintf("$%d", argsCount+i+1))
 			args = append(args, v.Addr)
 			haspk = true
+			i++
 		}
 	}
 
@@ -310,6 +542,7 @@ func (e SQLite) whereUniqueKey(fields []field.FieldInstance, argsCount int) (str
 			sb.WriteString(e.QuotedIdentifier(v.Schema.Name) + " = " + fmt.Sprintf("$%d", argsCount+i+1))
 			args = append(args, v.Addr)
 			hasuk = true
+			i++
 		}
 	}
 
@@ -317,6 +550,23 @@ func (e SQLite) whereUniqueKey(fields []field.FieldInstance, argsCount int) (str
 }
 
 func (e SQLite) QuotedIdentifier(i string) string { return fmt.Sprintf("\"%s\"", i) }
-func (e SQLite) QuotedValue(v any) string         { return "" }
-func (e SQLite) DefaultRandomUUID() string        { return "(gen_random_uuid())" }
-func (e SQLite) DefaultCurrentTimestamp() string  { return "current_timestamp" }
+
+// QuotedValue retorna o valor no formato literal do sql
+func (e SQLite) QuotedValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "'" + strings.ReplaceAll(t, "'", "''") + "'"
+	case bool:
+		if t {
+			return "1"
+		}
+		return "0"
+	case int, int16, int32, int64, float64:
+		return fmt.Sprint(t)
+	}
+	return e.QuotedValue(fmt.Sprint(v))
+}
+func (e SQLite) DefaultRandomUUID() string       { return "(gen_random_uuid())" }
+func (e SQLite) DefaultCurrentTimestamp() string { return "current_timestamp" }
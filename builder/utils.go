@@ -0,0 +1,45 @@
+package builder
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/dopsilva/rdd/schema"
+)
+
+// tableColumns retorna as colunas da tabela ordenadas pelo nome
+func tableColumns(table *schema.Table) []string {
+	columns := make([]string, 0, len(table.Fields))
+	for k := range table.Fields {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// enumFields retorna os campos com valores enumerados ordenados pelo nome
+func enumFields(table *schema.Table) []schema.Field {
+	fields := make([]schema.Field, 0)
+	for _, f := range table.Fields {
+		if len(f.Enum) > 0 {
+			fields = append(fields, f)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	return fields
+}
+
+// enumLiteral retorna o valor enumerado como literal de acordo com o tipo da coluna
+func enumLiteral(b Builder, f schema.Field, v string) string {
+	switch f.FieldType {
+	case "int", "int64", "int32", "int16", "NullInt64", "NullInt32", "NullInt16":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return b.QuotedValue(n)
+		}
+	case "float64", "NullFloat64":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return b.QuotedValue(n)
+		}
+	}
+	return b.QuotedValue(v)
+}
@@ -0,0 +1,302 @@
+package rdd
+
+import (
+	"container/list"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheEntry são os valores das colunas da entidade no formato do driver
+type CacheEntry map[string]any
+
+// CacheBackend é o armazenamento do cache de uma entidade
+type CacheBackend interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry, ttl time.Duration)
+	// Clear remove todas as entradas. É executado quando a entidade é alterada.
+	Clear()
+}
+
+// CacheOptions são as opções do cache da entidade
+type CacheOptions struct {
+	// TTL é o tempo de vida das entradas. Zero não expira as entradas.
+	TTL time.Duration
+	// Size é a quantidade máxima de entradas do LRU padrão
+	Size int
+	// Backend é o armazenamento do cache. O padrão é o LRU em memória.
+	Backend CacheBackend
+}
+
+var (
+	caches      = make(map[reflect.Type]*entityCache)
+	cachesMutex sync.RWMutex
+)
+
+type entityCache struct {
+	backend CacheBackend
+	ttl     time.Duration
+
+	// generation é incrementada a cada invalidação, para descartar as entidades lidas
+	// do banco de dados antes da invalidação
+	mutex      sync.Mutex
+	generation uint64
+}
+
+// EnableCache habilita o cache dos resultados do Seek e SeekUnique da entidade. O cache é
+// invalidado quando uma entidade do tipo é adicionada, alterada ou removida, após o Commit
+// da transação. Dentro de uma transação o cache é apenas consultado e não é utilizado se a
+// transação alterou uma entidade do tipo.
+func EnableCache[T any](options CacheOptions) {
+	c := &entityCache{backend: options.Backend, ttl: options.TTL}
+	if c.backend == nil {
+		c.backend = NewLRUCache(options.Size)
+	}
+
+	cachesMutex.Lock()
+	defer cachesMutex.Unlock()
+
+	caches[entityType[T]()] = c
+}
+
+// DisableCache desabilita o cache da entidade
+func DisableCache[T any]() {
+	cachesMutex.Lock()
+	defer cachesMutex.Unlock()
+
+	delete(caches, entityType[T]())
+}
+
+func cacheOf(entity reflect.Type) (*entityCache, bool) {
+	cachesMutex.RLock()
+	defer cachesMutex.RUnlock()
+
+	c, ok := caches[entity]
+	return c, ok
+}
+
+// invalidateCache remove as entradas do cache da entidade
+func invalidateCache(entity reflect.Type) {
+	if c, ok := cacheOf(entity); ok {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		c.generation++
+		c.backend.Clear()
+	}
+}
+
+// cacheGeneration retorna a geração do cache da entidade, obtida antes da consulta ao
+// banco de dados e verificada pelo toCache
+func cacheGeneration(entity reflect.Type) uint64 {
+	if c, ok := cacheOf(entity); ok {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		return c.generation
+	}
+	return 0
+}
+
+// cacheKey retorna a chave do cache através dos valores da primary ou unique key
+func (w *workarea[T]) cacheKey(primary bool) (string, bool) {
+	columns := make([]string, 0)
+	for k, fi := range w.fields {
+		if (primary && fi.Schema.PrimaryKey) || (!primary && fi.Schema.UniqueKey) {
+			columns = append(columns, k)
+		}
+	}
+	if len(columns) == 0 {
+		return "", false
+	}
+	sort.Strings(columns)
+
+	var sb strings.Builder
+	if primary {
+		sb.WriteString("pk")
+	} else {
+		sb.WriteString("uk")
+	}
+	for _, c := range columns {
+		v := w.relationKey(c)
+		if v == nil {
+			return "", false
+		}
+		sb.WriteString(fmt.Sprintf(":%v", v))
+	}
+	return sb.String(), true
+}
+
+// fromCache carrega a entidade do cache
+func (w *workarea[T]) fromCache(db Database, primary bool) bool {
+	c, ok := cacheOf(w.entityType())
+	if !ok || w.pendingIn(db) {
+		return false
+	}
+	key, ok := w.cacheKey(primary)
+	if !ok {
+		return false
+	}
+	entry, ok := c.backend.Get(key)
+	if !ok {
+		return false
+	}
+
+	for k, v := range entry {
+		if fi, ok := w.fields[k]; ok {
+			if err := w.setField(fi, v); err != nil {
+				return false
+			}
+		}
+	}
+	w.persisted = true
+	w.Freeze()
+
+	return true
+}
+
+// toCache armazena a entidade carregada do banco de dados no cache, se o cache não foi
+// invalidado após a geração em que a consulta iniciou
+func (w *workarea[T]) toCache(db Database, generation uint64) {
+	c, ok := cacheOf(w.entityType())
+	if !ok || db.WithinTransaction() {
+		return
+	}
+
+	entry := make(CacheEntry, len(w.fields))
+	for k, fi := range w.fields {
+		v, err := fieldValue(fi)
+		if err != nil {
+			return
+		}
+		entry[k] = v
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.generation != generation {
+		return
+	}
+	for _, primary := range []bool{true, false} {
+		if key, ok := w.cacheKey(primary); ok {
+			c.backend.Set(key, entry, c.ttl)
+		}
+	}
+}
+
+// pendingIn verifica se a transação, ou um dos savepoints até a transação, possui
+// alterações de entidades do tipo
+func (w *workarea[T]) pendingIn(db Database) bool {
+	tx, ok := db.(*TransactionWrapper)
+	if !ok {
+		return false
+	}
+	for ; tx != nil; tx = tx.parent {
+		for _, v := range tx.workareas {
+			if _, ok := any(v).(*T); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// committed é executado no Commit da transação em que a entidade foi gravada
+func (w *workarea[T]) committed() {
+	invalidateCache(w.entityType())
+}
+
+// LRUCache é o CacheBackend em memória que descarta as entradas menos utilizadas
+type LRUCache struct {
+	mutex sync.Mutex
+	size  int
+	items map[string]*list.Element
+	order *list.List
+}
+
+type lruItem struct {
+	key     string
+	entry   CacheEntry
+	expires time.Time
+}
+
+// NewLRUCache cria o LRUCache com a quantidade máxima de entradas. Se não informada são 1000 entradas.
+func NewLRUCache(size int) *LRUCache {
+	if size <= 0 {
+		size = 1000
+	}
+	return &LRUCache{size: size, items: make(map[string]*list.Element), order: list.New()}
+}
+
+func (c *LRUCache) Get(key string) (CacheEntry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	item := e.Value.(*lruItem)
+	if !item.expires.IsZero() && time.Now().After(item.expires) {
+		c.order.Remove(e)
+		delete(c.items, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(e)
+
+	return copyEntry(item.entry), true
+}
+
+func (c *LRUCache) Set(key string, entry CacheEntry, ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item := &lruItem{key: key, entry: copyEntry(entry)}
+	if ttl > 0 {
+		item.expires = time.Now().Add(ttl)
+	}
+
+	if e, ok := c.items[key]; ok {
+		e.Value = item
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(item)
+
+	for c.order.Len() > c.size {
+		e := c.order.Back()
+		c.order.Remove(e)
+		delete(c.items, e.Value.(*lruItem).key)
+	}
+}
+
+func (c *LRUCache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	clear(c.items)
+	c.order.Init()
+}
+
+// Len retorna a quantidade de entradas
+func (c *LRUCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+func copyEntry(entry CacheEntry) CacheEntry {
+	c := make(CacheEntry, len(entry))
+	for k, v := range entry {
+		c[k] = v
+	}
+	return c
+}
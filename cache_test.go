@@ -0,0 +1,221 @@
+package rdd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dopsilva/rdd/engine"
+	"github.com/dopsilva/rdd/field"
+)
+
+type Moeda struct {
+	Workarea[Moeda] `rdd-table:"moedas"`
+
+	Codigo  field.Field[string] `rdd-column:"codigo" rdd-primary-key:"true"`
+	Simbolo field.Field[string] `rdd-column:"simbolo" rdd-unique-key:"true"`
+	Nome    field.Field[string] `rdd-column:"nome"`
+}
+
+func init() {
+	Register[Moeda]()
+}
+
+func TestCache(t *testing.T) {
+	EnableCache[Moeda](CacheOptions{TTL: time.Minute, Size: 10})
+	defer DisableCache[Moeda]()
+
+	queries := 0
+	counter := func(ctx context.Context, call *Call, next Handler) (*CallResult, error) {
+		if call.Kind == QueryCall || call.Kind == QueryRowCall {
+			queries++
+		}
+		return next(ctx, call)
+	}
+
+	db, err := Connect(engine.SQLite, ":memory:", WithInterceptors(counter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.CreateTable(schemaOf[Moeda](), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("insert into moedas (codigo, simbolo, nome) values ('BRL', 'R$', 'Real')"); err != nil {
+		t.Fatal(err)
+	}
+
+	seek := func(db Database) (*Moeda, error) {
+		m := Use[Moeda]()
+		m.Codigo.Set("BRL")
+		return m, m.Seek(testContext, db)
+	}
+
+	m, err := seek(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	if queries != 1 {
+		t.Fatalf("esperado 1 consulta obtido %d", queries)
+	}
+
+	// as próximas leituras utilizam o cache, pela primary key e unique key
+	c, err := seek(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Reset()
+	c.Simbolo.Set("R$")
+	if err := c.SeekUnique(testContext, db); err != nil {
+		t.Fatal(err)
+	}
+	if queries != 1 || c.Nome.Get() != "Real" || c.Codigo.Get() != "BRL" || c.Changed() {
+		t.Fatalf("esperado o valor do cache obtido %s com %d consultas", c.Nome.Get(), queries)
+	}
+	c.Close()
+
+	// a alteração na transação invalida o cache somente após o commit
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Nome.Set("Real brasileiro")
+	if err := m.Replace(testContext, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = seek(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Nome.Get() != "Real" {
+		t.Fatalf("esperado o valor do cache antes do commit obtido %s", c.Nome.Get())
+	}
+	c.Close()
+
+	if err := tx.Commit(testContext); err != nil {
+		t.Fatal(err)
+	}
+
+	queries = 0
+	c, err = seek(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queries != 1 || c.Nome.Get() != "Real brasileiro" {
+		t.Fatalf("esperado o valor do banco de dados obtido %s com %d consultas", c.Nome.Get(), queries)
+	}
+	c.Close()
+
+	// a alteração no savepoint é lida do banco de dados dentro do savepoint
+	tx, err = db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sp, err := tx.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := seek(sp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Nome.Set("Novo Real")
+	if err := s.Replace(testContext, sp); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	c, err = seek(sp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Nome.Get() != "Novo Real" {
+		t.Fatalf("esperado o valor do savepoint obtido %s", c.Nome.Get())
+	}
+	c.Close()
+
+	if err := sp.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	// fora da transação o cache é invalidado imediatamente
+	if err := m.Remove(testContext, db); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := seek(db); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("esperado %v obtido %v", ErrNotFound, err)
+	}
+}
+
+func TestCacheInvalidatedDuringSeek(t *testing.T) {
+	EnableCache[Moeda](CacheOptions{})
+	defer DisableCache[Moeda]()
+
+	// o commit concorrente invalida o cache durante a consulta do Seek
+	invalidate := func(ctx context.Context, call *Call, next Handler) (*CallResult, error) {
+		if call.Kind == QueryRowCall {
+			invalidateCache(entityType[Moeda]())
+		}
+		return next(ctx, call)
+	}
+
+	db, err := Connect(engine.SQLite, ":memory:", WithInterceptors(invalidate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.CreateTable(schemaOf[Moeda](), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("insert into moedas (codigo, simbolo, nome) values ('BRL', 'R$', 'Real')"); err != nil {
+		t.Fatal(err)
+	}
+
+	m := Use[Moeda]()
+	defer m.Close()
+	m.Codigo.Set("BRL")
+	if err := m.Seek(testContext, db); err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := cacheOf(entityType[Moeda]())
+	if n := c.backend.(*LRUCache).Len(); n != 0 {
+		t.Fatalf("esperado cache vazio obtido %d entradas", n)
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+
+	c.Set("a", CacheEntry{"id": 1}, 0)
+	c.Set("b", CacheEntry{"id": 2}, 0)
+	c.Get("a")
+	c.Set("c", CacheEntry{"id": 3}, 0)
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("esperado descarte da entrada menos utilizada")
+	}
+	if e, ok := c.Get("a"); !ok || e["id"] != 1 {
+		t.Fatal("esperado a entrada utilizada recentemente")
+	}
+
+	c.Set("d", CacheEntry{"id": 4}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.Get("d"); ok {
+		t.Fatal("esperado expiração da entrada")
+	}
+
+	c.Clear()
+	if c.Len() != 0 {
+		t.Fatalf("esperado cache vazio obtido %d", c.Len())
+	}
+}
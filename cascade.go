@@ -0,0 +1,220 @@
+package rdd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dopsilva/rdd/builder"
+	"github.com/dopsilva/rdd/field"
+	"github.com/dopsilva/rdd/schema"
+)
+
+type cascadeKey struct{}
+
+// cascadeState registra as entidades já processadas pela operação em cascata,
+// evitando ciclos como o auto relacionamento de usuarios.incluido_por
+type cascadeState struct {
+	visited map[any]bool
+}
+
+// visit marca a entidade como processada, retornando falso se ela já foi processada
+func (s *cascadeState) visit(key any) bool {
+	if s.visited[key] {
+		return false
+	}
+	s.visited[key] = true
+	return true
+}
+
+func cascadeFrom(ctx context.Context) (*cascadeState, bool) {
+	s, ok := ctx.Value(cascadeKey{}).(*cascadeState)
+	return s, ok
+}
+
+// runCascade executa a operação em cascata em uma transação. Se o banco de dados
+// já estiver em uma transação é utilizado um savepoint.
+func runCascade(ctx context.Context, db Database, fn func(ctx context.Context, db Database) error) error {
+	if _, ok := cascadeFrom(ctx); ok {
+		return fn(ctx, db)
+	}
+
+	ctx = context.WithValue(ctx, cascadeKey{}, &cascadeState{visited: make(map[any]bool)})
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(ctx, tx); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit(ctx)
+}
+
+// hasCascade verifica se algum relacionamento da entidade executa as operações em cascata
+func (w *workarea[T]) hasCascade(actions ...string) bool {
+	for _, r := range w.schema.Relations {
+		if r.Cascades(actions...) {
+			return true
+		}
+	}
+	return false
+}
+
+// visit marca a entidade como processada pela operação em cascata
+func (w *workarea[T]) visit(ctx context.Context, key any) bool {
+	if s, ok := cascadeFrom(ctx); ok {
+		return s.visit(key)
+	}
+	return true
+}
+
+// identity identifica a entidade pela tabela e primary key
+func (w *workarea[T]) identity() any {
+	columns := make([]string, 0)
+	for k, v := range w.fields {
+		if v.Schema.PrimaryKey {
+			columns = append(columns, k)
+		}
+	}
+	if len(columns) == 0 {
+		return w.entity
+	}
+	sort.Strings(columns)
+
+	var sb strings.Builder
+	sb.WriteString(w.schema.Name)
+	for _, c := range columns {
+		sb.WriteString(fmt.Sprintf(":%v", w.relationKey(c)))
+	}
+	return sb.String()
+}
+
+// saveParents grava as entidades dos relacionamentos BelongsTo antes da entidade
+func (w *workarea[T]) saveParents(ctx context.Context, db Database) error {
+	for _, rel := range w.schema.Relations {
+		if rel.Kind != schema.BelongsTo || !rel.Cascades(schema.CascadeSave) {
+			continue
+		}
+		key, err := w.relations[rel.Name].cascadeSave(ctx, db, rel, nil)
+		if err != nil {
+			return err
+		}
+		if key != nil {
+			if err := w.setField(w.fields[rel.Column], key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// saveChildren grava as entidades dos relacionamentos HasMany depois da entidade
+func (w *workarea[T]) saveChildren(ctx context.Context, db Database) error {
+	for _, rel := range w.schema.Relations {
+		if rel.Kind != schema.HasMany || !rel.Cascades(schema.CascadeSave) {
+			continue
+		}
+		column, err := ownerColumn(w.schema, rel)
+		if err != nil {
+			return err
+		}
+		if _, err := w.relations[rel.Name].cascadeSave(ctx, db, rel, w.relationKey(column)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeChildren remove as entidades relacionadas antes da entidade
+func (w *workarea[T]) removeChildren(ctx context.Context, db Database) error {
+	for _, rel := range w.schema.Relations {
+		if !rel.Cascades(schema.CascadeRemove, schema.CascadeSoftDelete) {
+			continue
+		}
+		column, err := ownerColumn(w.schema, rel)
+		if err != nil {
+			return err
+		}
+		key := w.relationKey(column)
+		if key == nil {
+			continue
+		}
+		if err := w.relations[rel.Name].cascadeRemove(ctx, db, rel, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markRemoved define o valor da coluna de remoção lógica
+func (w *workarea[T]) markRemoved() error {
+	column, ok := w.schema.SoftDeleteColumn()
+	if !ok {
+		return fmt.Errorf("rdd: %s has no soft-delete column", w.Entity())
+	}
+
+	fi := w.fields[column]
+	switch fi.Schema.FieldType {
+	case "bool", "NullBool":
+		return w.setField(fi, true)
+	case "Time", "NullTime":
+		return w.setField(fi, time.Now())
+	}
+	return fmt.Errorf("rdd: soft-delete column %s must be bool or time", column)
+}
+
+// notRemovedCondition retorna a condição dos registros que não foram removidos logicamente
+func notRemovedCondition(table *schema.Table) (builder.Condition, bool) {
+	column, ok := table.SoftDeleteColumn()
+	if !ok {
+		return builder.Condition{}, false
+	}
+
+	switch table.Fields[column].FieldType {
+	case "bool", "NullBool":
+		return builder.Condition{Any: [][]builder.Condition{
+			{{Column: column, Operator: "=", Values: []any{false}}},
+			{{Column: column, Operator: "is null"}},
+		}}, true
+	}
+	return builder.Condition{Column: column, Operator: "is null"}, true
+}
+
+// saveEntity grava a entidade relacionada com Append se ela não existe no banco de dados
+// ou com Replace se foi alterada
+func saveEntity[T any](ctx context.Context, db Database, e *T) error {
+	w := any(e).(Workarea[T])
+	if !w.instance().persisted {
+		return w.Append(ctx, db)
+	}
+	if w.Changed() {
+		return w.Replace(ctx, db)
+	}
+	return nil
+}
+
+// removeEntity remove a entidade relacionada de acordo com a operação em cascata
+func removeEntity[T any](ctx context.Context, db Database, e *T, rel schema.Relation) error {
+	w := any(e).(Workarea[T])
+	if rel.Cascades(schema.CascadeSoftDelete) {
+		return w.SoftRemove(ctx, db)
+	}
+	return w.Remove(ctx, db)
+}
+
+// assignKey define a coluna da entidade relacionada com a chave da entidade proprietária
+func assignKey[T any](e *T, column string, key any) error {
+	w := any(e).(Workarea[T]).instance()
+	fi, ok := w.fields[column]
+	if !ok {
+		return fmt.Errorf("rdd: column %s not defined on %s", column, w.Entity())
+	}
+	if f, ok := fi.Addr.(field.Assignable); ok {
+		return f.Assign(key)
+	}
+	return fmt.Errorf("workarea: field %s does not accept %T", column, key)
+}
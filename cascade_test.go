@@ -0,0 +1,203 @@
+package rdd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dopsilva/rdd/engine"
+	"github.com/dopsilva/rdd/field"
+	"github.com/dopsilva/rdd/schema"
+)
+
+var (
+	pastasRemovidas   int
+	errPastaBloqueada = errors.New("pasta bloqueada")
+)
+
+type Pasta struct {
+	Workarea[Pasta] `rdd-table:"pastas"`
+
+	ID    field.Field[int64]    `rdd-column:"id" rdd-primary-key:"true" rdd-auto-generated:"true"`
+	Nome  field.Field[string]   `rdd-column:"nome"`
+	PaiID field.Nullable[int64] `rdd-column:"pai_id"`
+
+	Subpastas  HasMany[Pasta]     `rdd-has-many:"pai_id" rdd-cascade:"remove,save"`
+	Documentos HasMany[Documento] `rdd-has-many:"pasta_id" rdd-cascade:"soft-delete,save"`
+}
+
+func (p *Pasta) BeforeRemove(params EventParameters) error {
+	if p.Nome.Get() == "bloqueada" {
+		return errPastaBloqueada
+	}
+	pastasRemovidas++
+	return nil
+}
+
+type Documento struct {
+	Workarea[Documento] `rdd-table:"documentos"`
+
+	ID         field.Field[int64]        `rdd-column:"id" rdd-primary-key:"true" rdd-auto-generated:"true"`
+	PastaID    field.Field[int64]        `rdd-column:"pasta_id"`
+	Nome       field.Field[string]       `rdd-column:"nome"`
+	RemovidoEm field.Nullable[time.Time] `rdd-column:"removido_em" rdd-soft-delete:"true"`
+}
+
+type DocumentoInvalido struct {
+	Workarea[DocumentoInvalido] `rdd-table:"documentos_invalidos"`
+
+	ID         field.Field[int64]        `rdd-column:"id" rdd-primary-key:"true"`
+	RemovidoEm field.Nullable[time.Time] `rdd-column:"removido_em" rdd-soft-delete:"sim"`
+}
+
+func init() {
+	Register[Pasta]()
+	Register[Documento]()
+}
+
+func TestCascade(t *testing.T) {
+	pastasRemovidas = 0
+
+	db, err := Connect(engine.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, e := range []*schema.Table{schemaOf[Pasta](), schemaOf[Documento]()} {
+		if err := db.CreateTable(e, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count := func(q string) int {
+		var n int
+		if err := db.QueryRow(q).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	// grava as entidades relacionadas
+	raiz, sub, doc := Use[Pasta](), Use[Pasta](), Use[Documento]()
+	defer raiz.Close()
+	defer sub.Close()
+	defer doc.Close()
+
+	raiz.Nome.Set("raiz")
+	sub.Nome.Set("sub")
+	doc.Nome.Set("contrato")
+	sub.Documentos.Set(doc)
+	raiz.Subpastas.Set(sub)
+
+	if err := raiz.Append(testContext, db); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := sub.PaiID.Get(); v != raiz.ID.Get() || doc.PastaID.Get() != sub.ID.Get() {
+		t.Fatal("chaves das entidades relacionadas não definidas")
+	}
+	if count("select count(*) from pastas") != 2 || count("select count(*) from documentos") != 1 {
+		t.Fatal("entidades relacionadas não gravadas")
+	}
+
+	// ciclo no auto relacionamento
+	if _, err := db.Exec("update pastas set pai_id = id where nome = 'raiz'"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Select[Pasta](db, "select id, nome, pai_id from pastas where nome = 'raiz'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Close()
+
+	if err := res[0].Remove(testContext, db); err != nil {
+		t.Fatal(err)
+	}
+	if pastasRemovidas != 2 {
+		t.Fatalf("esperado 2 eventos de remoção obtido %d", pastasRemovidas)
+	}
+	if count("select count(*) from pastas") != 0 {
+		t.Fatal("esperado pastas removidas")
+	}
+	if count("select count(*) from documentos where removido_em is not null") != 1 {
+		t.Fatal("esperado documento removido logicamente")
+	}
+
+	// a falha em uma entidade relacionada desfaz toda a operação
+	if _, err := db.Exec("insert into pastas (id, nome) values (10, 'x'); insert into pastas (id, nome, pai_id) values (11, 'bloqueada', 10)"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err = Select[Pasta](db, "select id, nome, pai_id from pastas where id = 10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Close()
+
+	if err := res[0].Remove(testContext, db); !errors.Is(err, errPastaBloqueada) {
+		t.Fatalf("esperado %v obtido %v", errPastaBloqueada, err)
+	}
+	if count("select count(*) from pastas") != 2 {
+		t.Fatal("esperado remoção desfeita")
+	}
+}
+
+func TestCascadeSoftDeleted(t *testing.T) {
+	db, err := Connect(engine.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, e := range []*schema.Table{schemaOf[Pasta](), schemaOf[Documento]()} {
+		if err := db.CreateTable(e, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, q := range []string{
+		"insert into pastas (id, nome) values (1, 'raiz')",
+		"insert into documentos (id, pasta_id, nome, removido_em) values (1, 1, 'antigo', '2020-01-01 00:00:00')",
+		"insert into documentos (id, pasta_id, nome) values (2, 1, 'atual')",
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := Select[Pasta](db, "select id, nome, pai_id from pastas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Close()
+
+	if err := res[0].Remove(testContext, db); err != nil {
+		t.Fatal(err)
+	}
+
+	// o documento já removido mantém a data da remoção
+	docs, err := Select[Documento](db, "select id, pasta_id, nome, removido_em from documentos order by id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer docs.Close()
+
+	if v, ok := docs[0].RemovidoEm.Get(); !ok || v.Year() != 2020 {
+		t.Fatalf("esperado documento removido em 2020 obtido %v", v)
+	}
+	if v, ok := docs[1].RemovidoEm.Get(); !ok || v.Year() == 2020 {
+		t.Fatalf("esperado documento removido agora obtido %v", v)
+	}
+}
+
+func TestInvalidSoftDelete(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		if err == nil || !strings.Contains(err.Error(), "rdd-soft-delete on removido_em") {
+			t.Fatalf("esperado panic do rdd-soft-delete inválido obtido %v", err)
+		}
+	}()
+
+	Register[DocumentoInvalido]()
+}
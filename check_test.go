@@ -0,0 +1,77 @@
+package rdd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dopsilva/rdd/field"
+	"github.com/dopsilva/rdd/schema"
+)
+
+type Pedido struct {
+	Workarea[Pedido] `rdd-table:"pedidos"`
+
+	ID         field.Field[string]  `rdd-column:"id" rdd-primary-key:"true" rdd-auto-generated:"true" rdd-default:"new_uuid"`
+	Quantidade field.Field[int64]   `rdd-column:"quantidade" rdd-check:"quantidade > 0"`
+	Preco      field.Field[float64] `rdd-column:"preco"`
+	Desconto   field.Field[float64] `rdd-column:"desconto"`
+	Total      field.Field[float64] `rdd-column:"total" rdd-generated:"quantidade * preco - desconto" rdd-required:"true"`
+
+	ConstraintPreco field.Constraint `rdd-check:"preco >= 0" rdd-check-name:"ck_pedidos_preco"`
+}
+
+// DefineSchema complementa o schema com a restrição do desconto
+func (p *Pedido) DefineSchema(table *schema.Table) {
+	table.Checks = append(table.Checks, schema.Check{Name: "ck_pedidos_desconto", Expression: "desconto <= quantidade * preco"})
+}
+
+func init() {
+	Register[Pedido]()
+}
+
+func TestCheckAndGenerated(t *testing.T) {
+	defer truncateTable(testDatabase, "pedidos")
+
+	p := Use[Pedido]()
+	defer p.Close()
+
+	if len(p.Schema().Checks) != 2 {
+		t.Fatalf("esperado 2 checks obtido %v", p.Schema().Checks)
+	}
+	if !p.Schema().Fields["total"].Computed() {
+		t.Fatal("esperado total gerado pelo banco de dados")
+	}
+
+	p.Quantidade.Set(3)
+	p.Preco.Set(10)
+	p.Desconto.Set(5)
+
+	if err := p.Append(testContext, testDatabase); err != nil {
+		t.Fatal(err)
+	}
+	if p.Total.Get() != 25 {
+		t.Fatalf("esperado total 25 obtido %v", p.Total.Get())
+	}
+
+	p.Quantidade.Set(4)
+	if err := p.Replace(testContext, testDatabase); err != nil {
+		t.Fatal(err)
+	}
+	if p.Total.Get() != 35 {
+		t.Fatalf("esperado total 35 obtido %v", p.Total.Get())
+	}
+
+	for _, q := range []string{
+		"update pedidos set quantidade = 0",
+		"update pedidos set preco = -1",
+		"update pedidos set desconto = 100",
+	} {
+		if _, err := testDatabase.Exec(q); !errors.Is(err, CheckViolation) {
+			t.Fatalf("%s: esperado %v obtido %v", q, CheckViolation, err)
+		}
+	}
+
+	if _, err := testDatabase.Exec("update pedidos set total = 1"); err == nil {
+		t.Fatal("esperado erro na alteração da coluna gerada")
+	}
+}
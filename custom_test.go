@@ -0,0 +1,122 @@
+package rdd
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dopsilva/rdd/engine"
+	"github.com/dopsilva/rdd/field"
+	"github.com/dopsilva/rdd/schema"
+)
+
+// CPF é um tipo de domínio gravado somente com os dígitos
+type CPF string
+
+func (c CPF) Value() (driver.Value, error) {
+	return strings.NewReplacer(".", "", "-", "").Replace(string(c)), nil
+}
+
+func (c *CPF) Scan(src any) error {
+	s, ok := src.(string)
+	if !ok || len(s) != 11 {
+		return fmt.Errorf("cpf inválido %v", src)
+	}
+	*c = CPF(s[0:3] + "." + s[3:6] + "." + s[6:9] + "-" + s[9:])
+	return nil
+}
+
+// Telefones é um tipo não comparável que necessita de comparador
+type Telefones []string
+
+func (t Telefones) Value() (driver.Value, error) {
+	return strings.Join(t, ","), nil
+}
+
+func (t *Telefones) Scan(src any) error {
+	s, _ := src.(string)
+	*t = strings.Split(s, ",")
+	return nil
+}
+
+type Pessoa struct {
+	Workarea[Pessoa] `rdd-table:"pessoas"`
+
+	CPF       field.Custom[CPF]       `rdd-column:"cpf" rdd-primary-key:"true"`
+	Telefones field.Custom[Telefones] `rdd-column:"telefones"`
+}
+
+func init() {
+	field.RegisterType(field.TypeOptions[CPF]{
+		Columns: map[engine.Engine]string{engine.SQLite: "text"},
+	})
+	field.RegisterType(field.TypeOptions[Telefones]{
+		Equal:   func(a, b Telefones) bool { return slices.Equal(a, b) },
+		Columns: map[engine.Engine]string{engine.SQLite: "text"},
+	})
+
+	Register[Pessoa]()
+}
+
+func TestCustomField(t *testing.T) {
+	defer truncateTable(testDatabase, "pessoas")
+
+	p := Use[Pessoa]()
+	defer p.Close()
+
+	if p.Schema().Fields["cpf"].FieldType != "rdd.CPF" {
+		t.Fatalf("esperado tipo rdd.CPF obtido %s", p.Schema().Fields["cpf"].FieldType)
+	}
+
+	data := struct {
+		CPF       CPF       `rdd-column:"cpf"`
+		Telefones Telefones `rdd-column:"telefones"`
+	}{CPF: "123.456.789-09", Telefones: Telefones{"5199999999", "5188888888"}}
+
+	if err := p.Load(data); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Changed() {
+		t.Fatal("esperado alteração")
+	}
+
+	if err := p.Append(testContext, testDatabase); err != nil {
+		t.Fatal(err)
+	}
+
+	p.Telefones.Set(Telefones{"5199999999", "5188888888"})
+	if p.Changed() {
+		t.Fatal("não esperado alteração")
+	}
+
+	var cpf string
+	if err := testDatabase.QueryRow("select cpf from pessoas").Scan(&cpf); err != nil {
+		t.Fatal(err)
+	}
+	if cpf != "12345678909" {
+		t.Fatalf("esperado 12345678909 obtido %s", cpf)
+	}
+
+	res, err := Select[Pessoa](testDatabase, "select cpf, telefones from pessoas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Close()
+
+	if res.Len() != 1 || res[0].CPF.Get() != "123.456.789-09" || len(res[0].Telefones.Get()) != 2 {
+		t.Fatalf("valores inesperados %v", res)
+	}
+}
+
+func TestUnknownColumnType(t *testing.T) {
+	table := &schema.Table{
+		Name:   "desconhecidos",
+		Fields: map[string]schema.Field{"v": {Name: "v", FieldType: "rdd.Desconhecido"}},
+	}
+
+	if _, err := testDatabase.Builder().CreateTable(table, nil); err == nil {
+		t.Fatal("esperado erro de tipo desconhecido")
+	}
+}
@@ -3,20 +3,26 @@ package rdd
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/dopsilva/rdd/builder"
 	"github.com/dopsilva/rdd/field"
 	"github.com/dopsilva/rdd/schema"
 	"github.com/google/uuid"
-	"github.com/lib/pq"
-	sqlite "github.com/mattn/go-sqlite3"
 )
 
 type Database interface {
 	Exec(q string, args ...any) (sql.Result, error)
 	Query(q string, args ...any) (*sql.Rows, error)
-	QueryRow(q string, args ...any) *sql.Row
+	// QueryRow retorna *Row no lugar do *sql.Row, para que os erros do driver sejam
+	// convertidos para Error no Scan. O Row possui os mesmos métodos Scan e Err.
+	QueryRow(q string, args ...any) *Row
 
 	Begin() (Database, error)
 	Commit(ctx context.Context) error
@@ -24,6 +30,8 @@ type Database interface {
 
 	Close() error
 
+	Stats() sql.DBStats
+
 	Engine() DatabaseEngine
 	Builder() builder.Builder
 
@@ -36,26 +44,90 @@ type Database interface {
 }
 
 type DatabaseWrapper struct {
-	db      *sql.DB
-	engine  DatabaseEngine
-	builder builder.Builder
+	db           *sql.DB
+	engine       DatabaseEngine
+	builder      builder.Builder
+	interceptors []Interceptor
+
+	logger        *slog.Logger
+	slowThreshold time.Duration
+
+	observer      Observer
+	statsInterval time.Duration
+	done          chan struct{}
+	closeOnce     sync.Once
+}
+
+// Row é o resultado do QueryRow. Os erros do driver são convertidos para Error no Scan.
+type Row struct {
+	row *sql.Row
+	err error
+}
+
+// Scan lê as colunas da linha retornada
+func (r *Row) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	return translateError(r.row.Scan(dest...))
+}
+
+// Err retorna o erro da execução da query
+func (r *Row) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return translateError(r.row.Err())
 }
 
 func (db *DatabaseWrapper) Exec(q string, args ...any) (sql.Result, error) {
-	return db.db.Exec(q, args...)
+	return execContext(context.Background(), db, &Call{Query: q, Args: args})
 }
 
 func (db *DatabaseWrapper) Query(q string, args ...any) (*sql.Rows, error) {
-	return db.db.Query(q, args...)
+	return queryContext(context.Background(), db, &Call{Query: q, Args: args})
 }
 
-func (db *DatabaseWrapper) QueryRow(q string, args ...any) *sql.Row {
-	return db.db.QueryRow(q, args...)
+func (db *DatabaseWrapper) QueryRow(q string, args ...any) *Row {
+	return queryRowContext(context.Background(), db, &Call{Query: q, Args: args})
 }
 
 func (db *DatabaseWrapper) Begin() (Database, error) {
-	tx, err := db.db.Begin()
-	return &TransactionWrapper{db: db, tx: tx}, err
+	res, err := db.invoke(context.Background(), &Call{Kind: BeginCall})
+	if err != nil {
+		return nil, err
+	}
+	if res.Tx == nil {
+		return nil, errNoTransaction
+	}
+	return res.Tx, nil
+}
+
+// invoke executa a chamada através da cadeia de interceptors
+func (db *DatabaseWrapper) invoke(ctx context.Context, call *Call) (*CallResult, error) {
+	call.Database = db
+	return intercept(ctx, db.interceptors, call, db.execute)
+}
+
+// execute executa a chamada no banco de dados
+func (db *DatabaseWrapper) execute(ctx context.Context, call *Call) (*CallResult, error) {
+	switch call.Kind {
+	case ExecCall:
+		res, err := db.db.ExecContext(ctx, call.Query, call.Args...)
+		return &CallResult{Result: res}, err
+	case QueryCall:
+		rows, err := db.db.QueryContext(ctx, call.Query, call.Args...)
+		return &CallResult{Rows: rows}, err
+	case QueryRowCall:
+		return &CallResult{Row: &Row{row: db.db.QueryRowContext(ctx, call.Query, call.Args...)}}, nil
+	case BeginCall:
+		tx, err := db.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &CallResult{Tx: &TransactionWrapper{db: db, tx: tx, id: uuid.NewString(), start: time.Now()}}, nil
+	}
+	return nil, fmt.Errorf("rdd: unexpected %s call", call.Kind)
 }
 
 func (db *DatabaseWrapper) Commit(ctx context.Context) error {
@@ -67,9 +139,24 @@ func (db *DatabaseWrapper) Rollback() error {
 }
 
 func (db *DatabaseWrapper) Close() error {
+	// o Close pode ser executado mais de uma vez, como no sql.DB
+	db.closeOnce.Do(func() {
+		if db.done != nil {
+			close(db.done)
+		}
+	})
 	return db.db.Close()
 }
 
+// Stats retorna as estatísticas do pool de conexões
+func (db *DatabaseWrapper) Stats() sql.DBStats {
+	return db.db.Stats()
+}
+
+func (db *DatabaseWrapper) metrics() (Observer, string) {
+	return db.observer, db.engine.String()
+}
+
 func (db *DatabaseWrapper) WithinTransaction() bool {
 	return false
 }
@@ -99,27 +186,91 @@ func (db *DatabaseWrapper) CreateTable(table *schema.Table, options *builder.Cre
 		return err
 	}
 
-	return nil
+	// cria as tabelas de associação dos relacionamentos ManyToMany
+	return createJoinTables(db, table)
+}
+
+// restorable é implementada pelos objetos armazenados na transação que
+// retornam ao estado anterior no rollback
+type restorable interface {
+	Restore()
+}
+
+// committable é implementada pelas entidades que invalidam o cache após o commit
+type committable interface {
+	committed()
+}
+
+// retainable é implementada pelas entidades que não retornam ao pool no Close enquanto
+// estiverem armazenadas em uma transação, sendo devolvidas ao pool no release
+type retainable interface {
+	retain()
+	release()
+}
+
+// releaseWorkarea libera a workarea armazenada na transação
+func releaseWorkarea(f field.Freezable) {
+	if r, ok := f.(retainable); ok {
+		r.release()
+	}
 }
 
 type TransactionWrapper struct {
 	db        *DatabaseWrapper
 	tx        *sql.Tx
+	id        string // identificador da transação, compartilhado pelos savepoints
+	start     time.Time
 	workareas []field.Freezable
 	savepoint bool
-	spname    string // savepoint name
+	spname    string              // savepoint name
+	parent    *TransactionWrapper // transação do savepoint
+
+	identities *identityMap
 }
 
 func (tx *TransactionWrapper) Exec(q string, args ...any) (sql.Result, error) {
-	return tx.tx.Exec(q, args...)
+	return execContext(context.Background(), tx, &Call{Query: q, Args: args})
 }
 
 func (tx *TransactionWrapper) Query(q string, args ...any) (*sql.Rows, error) {
-	return tx.tx.Query(q, args...)
+	return queryContext(context.Background(), tx, &Call{Query: q, Args: args})
+}
+
+func (tx *TransactionWrapper) QueryRow(q string, args ...any) *Row {
+	return queryRowContext(context.Background(), tx, &Call{Query: q, Args: args})
+}
+
+// invoke executa a chamada através da cadeia de interceptors
+func (tx *TransactionWrapper) invoke(ctx context.Context, call *Call) (*CallResult, error) {
+	call.Database = tx
+	return intercept(ctx, tx.db.interceptors, call, tx.execute)
 }
 
-func (tx *TransactionWrapper) QueryRow(q string, args ...any) *sql.Row {
-	return tx.tx.QueryRow(q, args...)
+// execute executa a chamada na transação
+func (tx *TransactionWrapper) execute(ctx context.Context, call *Call) (*CallResult, error) {
+	switch call.Kind {
+	case ExecCall:
+		res, err := tx.tx.ExecContext(ctx, call.Query, call.Args...)
+		return &CallResult{Result: res}, err
+	case QueryCall:
+		rows, err := tx.tx.QueryContext(ctx, call.Query, call.Args...)
+		return &CallResult{Rows: rows}, err
+	case QueryRowCall:
+		return &CallResult{Row: &Row{row: tx.tx.QueryRowContext(ctx, call.Query, call.Args...)}}, nil
+	case BeginCall, CommitCall, RollbackCall:
+		// comandos de savepoint
+		if call.Query != "" {
+			res, err := tx.tx.ExecContext(ctx, call.Query, call.Args...)
+			return &CallResult{Result: res}, err
+		}
+		if call.Kind == CommitCall {
+			return nil, tx.tx.Commit()
+		}
+		if call.Kind == RollbackCall {
+			return nil, tx.tx.Rollback()
+		}
+	}
+	return nil, fmt.Errorf("rdd: unexpected %s call", call.Kind)
 }
 
 func (tx *TransactionWrapper) Close() error {
@@ -128,11 +279,11 @@ func (tx *TransactionWrapper) Close() error {
 
 func (tx *TransactionWrapper) Begin() (Database, error) {
 
-	ntx := &TransactionWrapper{tx: tx.tx, db: tx.db}
+	ntx := &TransactionWrapper{tx: tx.tx, db: tx.db, id: tx.id, start: tx.start, parent: tx}
 	ntx.savepoint = true
 	ntx.spname = "sp_" + strings.ReplaceAll(uuid.NewString(), "-", "")
 
-	if _, err := tx.Exec("savepoint " + ntx.spname); err != nil {
+	if _, err := tx.invoke(context.Background(), &Call{Kind: BeginCall, Query: "savepoint " + ntx.spname}); err != nil {
 		return nil, err
 	}
 
@@ -141,19 +292,41 @@ func (tx *TransactionWrapper) Begin() (Database, error) {
 
 func (tx *TransactionWrapper) Commit(ctx context.Context) error {
 	if !tx.savepoint {
-		if err := tx.tx.Commit(); err != nil {
+		if _, err := tx.invoke(ctx, &Call{Kind: CommitCall}); err != nil {
+			tx.observe(OutcomeError)
 			return err
 		}
+		tx.observe(OutcomeCommit)
+		tx.identities.clear()
 		// congela as workareas
 		for _, w := range tx.workareas {
-			// dispara o evento AfterCommit da workarea
-			any(w).(Triggable).AfterCommit(EventParameters{Context: ctx, Database: tx.db})
+			// invalida o cache da entidade
+			if c, ok := w.(committable); ok {
+				c.committed()
+			}
+			// dispara o evento AfterCommit da workarea. A transação já foi confirmada,
+			// portanto o erro é entregue aos OnSubscriberError do AfterCommit.
+			if t, ok := w.(Triggable); ok {
+				if err := t.AfterCommit(EventParameters{Context: ctx, Database: tx.db}); err != nil {
+					reportCommitError(ctx, w, err)
+				}
+			}
+			// executa os assinantes do AfterCommit
+			publishCommitted(ctx, w)
 			w.Freeze()
+			releaseWorkarea(w)
 		}
+		tx.workareas = nil
 	} else {
-		if _, err := tx.Exec("release savepoint " + tx.spname); err != nil {
+		if _, err := tx.invoke(ctx, &Call{Kind: CommitCall, Query: "release savepoint " + tx.spname}); err != nil {
 			return err
 		}
+		// as workareas do savepoint passam a pertencer à transação
+		for _, w := range tx.workareas {
+			tx.parent.StoreWorkarea(w)
+			releaseWorkarea(w)
+		}
+		tx.workareas = nil
 	}
 
 	return nil
@@ -161,21 +334,68 @@ func (tx *TransactionWrapper) Commit(ctx context.Context) error {
 
 func (tx *TransactionWrapper) Rollback() error {
 	if !tx.savepoint {
-		if err := tx.tx.Rollback(); err != nil {
+		if _, err := tx.invoke(context.Background(), &Call{Kind: RollbackCall}); err != nil {
+			tx.observe(OutcomeError)
 			return err
 		}
-		// congela as workareas
+		tx.observe(OutcomeRollback)
+		tx.identities.clear()
+		restoreWorkareas(tx.workareas)
 		for _, w := range tx.workareas {
-			w.Freeze()
+			releaseWorkarea(w)
 		}
+		tx.workareas = nil
 	} else {
-		if _, err := tx.Exec("rollback to " + tx.spname); err != nil {
+		if _, err := tx.invoke(context.Background(), &Call{Kind: RollbackCall, Query: "rollback to " + tx.spname}); err != nil {
 			return err
 		}
+		// descarta as workareas gravadas somente no savepoint. As gravadas antes do
+		// savepoint continuam na transação.
+		discarded := make([]field.Freezable, 0, len(tx.workareas))
+		for _, w := range tx.workareas {
+			if !tx.parent.stored(w) {
+				discarded = append(discarded, w)
+			}
+		}
+		restoreWorkareas(discarded)
+		for _, w := range tx.workareas {
+			releaseWorkarea(w)
+		}
+		tx.workareas = nil
 	}
 	return nil
 }
 
+// restoreWorkareas restaura ou congela as workareas desfeitas no rollback
+func restoreWorkareas(workareas []field.Freezable) {
+	for _, w := range workareas {
+		if r, ok := w.(restorable); ok {
+			r.Restore()
+		} else {
+			w.Freeze()
+		}
+	}
+}
+
+// observe informa a duração da transação ao observer
+func (tx *TransactionWrapper) observe(outcome string) {
+	if tx.db.observer != nil {
+		tx.db.observer.ObserveTransaction(TransactionMetric{
+			Engine:   tx.db.engine.String(),
+			Outcome:  outcome,
+			Duration: time.Since(tx.start),
+		})
+	}
+}
+
+func (tx *TransactionWrapper) Stats() sql.DBStats {
+	return tx.db.Stats()
+}
+
+func (tx *TransactionWrapper) metrics() (Observer, string) {
+	return tx.db.metrics()
+}
+
 func (tx *TransactionWrapper) WithinTransaction() bool {
 	return true
 }
@@ -196,29 +416,36 @@ func (tx *TransactionWrapper) CreateTable(table *schema.Table, options *builder.
 	return tx.db.CreateTable(table, options)
 }
 
+// StoreWorkarea armazena a workarea gravada na transação uma única vez, mesmo que gravada
+// mais de uma vez. Nos savepoints as workareas são transferidas para a transação no Commit
+// (release savepoint) e descartadas no Rollback (rollback to), portanto os savepoints devem
+// ser sempre confirmados ou desfeitos.
+//
+// As entidades armazenadas não retornam ao pool no Close até o Commit ou Rollback da
+// transação, para que o AfterCommit receba a entidade com os valores gravados.
 func (tx *TransactionWrapper) StoreWorkarea(f field.Freezable) {
+	if slices.Contains(tx.workareas, f) {
+		return
+	}
+	if r, ok := f.(retainable); ok {
+		r.retain()
+	}
 	tx.workareas = append(tx.workareas, f)
 }
 
-func IsDuplicatedError(err error) bool {
-	if err == nil {
-		return false
-	}
-
-	// cockroachdb/postgres
-	if verr, ok := err.(*pq.Error); ok {
-		if verr.Code == "23505" {
+// stored verifica se a workarea está armazenada na transação ou nos savepoints anteriores
+func (tx *TransactionWrapper) stored(f field.Freezable) bool {
+	for t := tx; t != nil; t = t.parent {
+		if slices.Contains(t.workareas, f) {
 			return true
 		}
 	}
+	return false
+}
 
-	// sqlite
+var errNoTransaction = errors.New("rdd: interceptor returned no transaction")
 
-	if verr, ok := err.(sqlite.Error); ok {
-		if verr.Code == 19 && (verr.ExtendedCode == 1555 || verr.ExtendedCode == 2067) {
-			return true
-		}
-	}
-
-	return false
+// IsDuplicatedError verifica se o erro é de violação de chave única
+func IsDuplicatedError(err error) bool {
+	return errors.Is(translateError(err), UniqueViolation)
 }
@@ -10,6 +10,18 @@ const (
 	SQLServer
 )
 
+func (e DatabaseEngine) String() string {
+	switch e {
+	case SQLite:
+		return "sqlite"
+	case Cockroach:
+		return "cockroach"
+	case SQLServer:
+		return "sqlserver"
+	}
+	return "unknown"
+}
+
 func (e DatabaseEngine) QuotedIdentifier(v any) string {
 	switch e {
 	case SQLite, Cockroach:
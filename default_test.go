@@ -0,0 +1,109 @@
+package rdd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dopsilva/rdd/field"
+	"github.com/dopsilva/rdd/schema"
+)
+
+type Tarefa struct {
+	Workarea[Tarefa] `rdd-table:"tarefas"`
+
+	ID         field.Field[string]  `rdd-column:"id" rdd-primary-key:"true" rdd-auto-generated:"true" rdd-default:"new_uuid"`
+	Titulo     field.Field[string]  `rdd-column:"titulo"`
+	Situacao   field.Field[string]  `rdd-column:"situacao" rdd-default:"'pending'"`
+	Tentativas field.Field[int64]   `rdd-column:"tentativas" rdd-default:"0"`
+	Peso       field.Field[float64] `rdd-column:"peso" rdd-default:"1.5"`
+	Ativa      field.Field[bool]    `rdd-column:"ativa" rdd-default:"true"`
+	Data       field.Field[string]  `rdd-column:"data" rdd-default:"current_date"`
+	Chave      field.Field[string]  `rdd-column:"chave" rdd-default:"'x'" rdd-default-sqlite:"lower(hex(randomblob(4)))"`
+}
+
+type TarefaInvalida struct {
+	Workarea[TarefaInvalida] `rdd-table:"tarefas_invalidas"`
+
+	ID       field.Field[string] `rdd-column:"id" rdd-primary-key:"true"`
+	Situacao field.Field[string] `rdd-column:"situacao" rdd-default:"pending"`
+}
+
+func init() {
+	Register[Tarefa]()
+}
+
+func TestParseDefault(t *testing.T) {
+	cases := []struct {
+		spec     string
+		function string
+		literal  any
+	}{
+		{"new_uuid", schema.DefaultNewUUID, nil},
+		{"now", schema.DefaultNow, nil},
+		{"null", "", nil},
+		{"TRUE", "", true},
+		{"-10", "", int64(-10)},
+		{"2.5", "", 2.5},
+		{"'it''s'", "", "it's"},
+	}
+
+	for _, c := range cases {
+		d, err := schema.ParseDefault(c.spec)
+		if err != nil {
+			t.Fatalf("%s: %v", c.spec, err)
+		}
+		if d.Function != c.function || d.Literal != c.literal {
+			t.Fatalf("%s: valor inesperado %+v", c.spec, d)
+		}
+	}
+
+	for _, spec := range []string{"pending", "'a'b'", "uuid()"} {
+		if _, err := schema.ParseDefault(spec); err == nil {
+			t.Fatalf("%s: esperado erro", spec)
+		}
+	}
+}
+
+func TestDefault(t *testing.T) {
+	defer truncateTable(testDatabase, "tarefas")
+
+	if _, err := testDatabase.Exec("insert into tarefas (titulo) values ('a')"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Select[Tarefa](testDatabase, "select id, titulo, situacao, tentativas, peso, ativa, data, chave from tarefas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Close()
+
+	r := res[0]
+	if r.ID.Get() == "" || r.Situacao.Get() != "pending" || r.Tentativas.Get() != 0 || r.Peso.Get() != 1.5 || !r.Ativa.Get() || r.Data.Get() == "" {
+		t.Fatalf("valores inesperados %+v", r)
+	}
+	if len(r.Chave.Get()) != 8 {
+		t.Fatalf("esperado a expressão da engine obtido %s", r.Chave.Get())
+	}
+}
+
+func TestInvalidDefault(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		if err == nil || !strings.Contains(err.Error(), "situacao") {
+			t.Fatalf("esperado panic do default inválido obtido %v", err)
+		}
+	}()
+
+	Register[TarefaInvalida]()
+}
+
+func TestUnsupportedDefault(t *testing.T) {
+	table := &schema.Table{
+		Name:   "padroes",
+		Fields: map[string]schema.Field{"v": {Name: "v", FieldType: "string", Default: "pending"}},
+	}
+
+	if _, err := testDatabase.Builder().CreateTable(table, nil); err == nil {
+		t.Fatal("esperado erro do default inválido")
+	}
+}
@@ -0,0 +1,72 @@
+package rdd
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/dopsilva/rdd/field"
+)
+
+type Assinatura struct {
+	Workarea[Assinatura] `rdd-table:"assinaturas"`
+
+	ID         field.Field[string] `rdd-column:"id" rdd-primary-key:"true" rdd-auto-generated:"true" rdd-default:"new_uuid"`
+	Situacao   field.Enum          `rdd-column:"situacao" rdd-enum:"active,blocked,deleted"`
+	Prioridade field.Field[int64]  `rdd-column:"prioridade" rdd-enum:"1,2,3"`
+}
+
+func init() {
+	Register[Assinatura]()
+}
+
+func TestEnum(t *testing.T) {
+	defer truncateTable(testDatabase, "assinaturas")
+
+	a := Use[Assinatura]()
+	defer a.Close()
+
+	if !slices.Equal(a.Schema().Fields["situacao"].Enum, []string{"active", "blocked", "deleted"}) {
+		t.Fatalf("enum inesperado %v", a.Schema().Fields["situacao"].Enum)
+	}
+
+	if err := a.Situacao.Set("paused"); !errors.Is(err, field.ErrInvalidEnum) {
+		t.Fatalf("esperado %v obtido %v", field.ErrInvalidEnum, err)
+	}
+	if err := a.Situacao.Set(""); !errors.Is(err, field.ErrInvalidEnum) {
+		t.Fatalf("esperado %v com o valor vazio obtido %v", field.ErrInvalidEnum, err)
+	}
+
+	// o enum sem valor não atende à constraint
+	a.Prioridade.Set(2)
+
+	var verrs ValidationErrors
+	if err := a.Append(testContext, testDatabase); !errors.As(err, &verrs) || verrs[0].Column != "situacao" || verrs[0].Rule != RuleEnum {
+		t.Fatalf("esperado erro de validação da situação obtido %v", err)
+	}
+
+	if err := a.Situacao.Set("active"); err != nil {
+		t.Fatal(err)
+	}
+
+	a.Prioridade.Set(5)
+
+	if err := a.Append(testContext, testDatabase); !errors.As(err, &verrs) || verrs[0].Column != "prioridade" {
+		t.Fatalf("esperado erro de validação da prioridade obtido %v", err)
+	}
+
+	a.Prioridade.Set(2)
+	if err := a.Append(testContext, testDatabase); err != nil {
+		t.Fatal(err)
+	}
+
+	// a constraint impede valores inválidos gravados diretamente
+	_, err := testDatabase.Exec("update assinaturas set situacao = 'paused'")
+	if !errors.Is(err, &Error{Kind: CheckViolation, Constraint: "ck_assinaturas_situacao_enum"}) {
+		t.Fatalf("esperado %v obtido %v", CheckViolation, err)
+	}
+	_, err = testDatabase.Exec("update assinaturas set prioridade = 4")
+	if !errors.Is(err, CheckViolation) {
+		t.Fatalf("esperado %v obtido %v", CheckViolation, err)
+	}
+}
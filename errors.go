@@ -0,0 +1,216 @@
+package rdd
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+	sqlite "github.com/mattn/go-sqlite3"
+)
+
+// ErrorKind classifica os erros retornados pelos drivers
+type ErrorKind int
+
+const (
+	UnknownError ErrorKind = iota
+	UniqueViolation
+	ForeignKeyViolation
+	NotNullViolation
+	CheckViolation
+	SerializationFailure
+	Deadlock
+	Timeout
+	ConnectionLost
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case UniqueViolation:
+		return "unique violation"
+	case ForeignKeyViolation:
+		return "foreign key violation"
+	case NotNullViolation:
+		return "not null violation"
+	case CheckViolation:
+		return "check violation"
+	case SerializationFailure:
+		return "serialization failure"
+	case Deadlock:
+		return "deadlock"
+	case Timeout:
+		return "timeout"
+	case ConnectionLost:
+		return "connection lost"
+	}
+	return "unknown error"
+}
+
+// Error permite o uso do ErrorKind como alvo do errors.Is
+func (k ErrorKind) Error() string {
+	return "rdd: " + k.String()
+}
+
+// Error é o erro retornado pelos wrappers do banco de dados. O erro original do
+// driver continua acessível através do errors.As.
+type Error struct {
+	Kind       ErrorKind
+	Table      string
+	Constraint string
+	Column     string
+	Err        error
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+
+	b.WriteString("rdd: " + e.Kind.String())
+	if e.Table != "" {
+		b.WriteString(" on table " + e.Table)
+	}
+	if e.Column != "" {
+		b.WriteString(" column " + e.Column)
+	}
+	if e.Constraint != "" {
+		b.WriteString(" constraint " + e.Constraint)
+	}
+	if e.Err != nil {
+		b.WriteString(": " + e.Err.Error())
+	}
+
+	return b.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is compara o tipo do erro, permitindo errors.Is(err, rdd.UniqueViolation)
+func (e *Error) Is(target error) bool {
+	switch t := target.(type) {
+	case ErrorKind:
+		return e.Kind == t
+	case *Error:
+		return e.Kind == t.Kind &&
+			(t.Table == "" || t.Table == e.Table) &&
+			(t.Constraint == "" || t.Constraint == e.Constraint) &&
+			(t.Column == "" || t.Column == e.Column)
+	}
+	return false
+}
+
+// translateError converte os erros dos drivers para o Error.
+// Erros que não são do driver são retornados sem alteração.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var rerr *Error
+	if errors.As(err, &rerr) {
+		return err
+	}
+
+	// cockroachdb/postgres
+	var perr *pq.Error
+	if errors.As(err, &perr) {
+		return &Error{
+			Kind:       postgresErrorKind(perr),
+			Table:      perr.Table,
+			Constraint: perr.Constraint,
+			Column:     perr.Column,
+			Err:        err,
+		}
+	}
+
+	// sqlite
+	var serr sqlite.Error
+	if errors.As(err, &serr) {
+		e := &Error{Kind: sqliteErrorKind(serr), Err: err}
+		sqliteErrorDetail(serr, e)
+		return e
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return &Error{Kind: Timeout, Err: err}
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone):
+		return &Error{Kind: ConnectionLost, Err: err}
+	}
+
+	return err
+}
+
+func postgresErrorKind(err *pq.Error) ErrorKind {
+	switch err.Code {
+	case "23505":
+		return UniqueViolation
+	case "23503":
+		return ForeignKeyViolation
+	case "23502":
+		return NotNullViolation
+	case "23514":
+		return CheckViolation
+	case "40001":
+		return SerializationFailure
+	case "40P01":
+		return Deadlock
+	case "57014", "55P03":
+		return Timeout
+	case "57P01", "57P02", "57P03":
+		return ConnectionLost
+	}
+
+	if err.Code.Class() == "08" {
+		return ConnectionLost
+	}
+
+	return UnknownError
+}
+
+func sqliteErrorKind(err sqlite.Error) ErrorKind {
+	switch err.Code {
+	case sqlite.ErrConstraint:
+		switch err.ExtendedCode {
+		case sqlite.ErrConstraintUnique, sqlite.ErrConstraintPrimaryKey, sqlite.ErrConstraintRowID:
+			return UniqueViolation
+		case sqlite.ErrConstraintForeignKey:
+			return ForeignKeyViolation
+		case sqlite.ErrConstraintNotNull:
+			return NotNullViolation
+		case sqlite.ErrConstraintCheck:
+			return CheckViolation
+		}
+	case sqlite.ErrBusy, sqlite.ErrLocked, sqlite.ErrInterrupt:
+		return Timeout
+	case sqlite.ErrIoErr, sqlite.ErrCantOpen:
+		return ConnectionLost
+	}
+	return UnknownError
+}
+
+// sqliteErrorDetail extrai a tabela, coluna ou constraint da mensagem do sqlite.
+// Ex.: "UNIQUE constraint failed: usuarios.email"
+func sqliteErrorDetail(err sqlite.Error, e *Error) {
+	const marker = "constraint failed: "
+
+	msg := err.Error()
+	i := strings.Index(msg, marker)
+	if i < 0 {
+		return
+	}
+	detail := msg[i+len(marker):]
+
+	switch e.Kind {
+	case UniqueViolation, NotNullViolation:
+		first, _, _ := strings.Cut(detail, ", ")
+		if table, column, ok := strings.Cut(first, "."); ok {
+			e.Table = table
+			e.Column = column
+		}
+	case CheckViolation:
+		e.Constraint = detail
+	}
+}
@@ -0,0 +1,82 @@
+package rdd
+
+import (
+	"errors"
+	"testing"
+
+	sqlite "github.com/mattn/go-sqlite3"
+)
+
+func TestConstraintErrors(t *testing.T) {
+	defer truncateTable(testDatabase, "usuarios")
+
+	q := "insert into usuarios (email, nome, incluido_em) values ($1, $2, current_timestamp)"
+
+	if _, err := testDatabase.Exec(q, "dopslv@gmail.com", "Daniel"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := testDatabase.Exec(q, "dopslv@gmail.com", "Daniel")
+	if !errors.Is(err, UniqueViolation) {
+		t.Fatalf("esperado %v obtido %v", UniqueViolation, err)
+	}
+	if !IsDuplicatedError(err) {
+		t.Fatal("esperado erro de chave duplicada")
+	}
+
+	var rerr *Error
+	if !errors.As(err, &rerr) {
+		t.Fatalf("esperado *Error obtido %T", err)
+	}
+	if rerr.Table != "usuarios" || rerr.Column != "email" {
+		t.Fatalf("esperado usuarios.email obtido %s.%s", rerr.Table, rerr.Column)
+	}
+
+	// o erro original do driver continua acessível
+	var serr sqlite.Error
+	if !errors.As(err, &serr) {
+		t.Fatal("esperado acesso ao erro do driver")
+	}
+
+	err = testDatabase.QueryRow("insert into usuarios (email, incluido_em) values ($1, current_timestamp) returning id", "outro@gmail.com").Scan(new(string))
+	if !errors.Is(err, NotNullViolation) {
+		t.Fatalf("esperado %v obtido %v", NotNullViolation, err)
+	}
+	if !errors.Is(err, &Error{Kind: NotNullViolation, Column: "nome"}) {
+		t.Fatalf("esperado violação na coluna nome obtido %v", err)
+	}
+}
+
+func TestAppendConstraintError(t *testing.T) {
+	defer truncateTable(testDatabase, "usuarios")
+
+	for i := 0; i < 2; i++ {
+		u := Use[Usuario]()
+		u.Email.Set("dopslv@gmail.com")
+		u.Nome.Set("Daniel")
+
+		err := u.Append(testContext, testDatabase)
+		u.Close()
+
+		if i == 0 {
+			if err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		// o erro do driver é retornado pelo Append como Error
+		var rerr *Error
+		if !errors.As(err, &rerr) || rerr.Kind != UniqueViolation || rerr.Column != "email" {
+			t.Fatalf("esperado %v em usuarios.email obtido %v", UniqueViolation, err)
+		}
+	}
+
+	var n int
+	if err := testDatabase.QueryRow("select count(*) from usuarios").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("esperado 1 registro obtido %d", n)
+	}
+}
@@ -0,0 +1,200 @@
+package rdd
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// EntityEvent identifica o evento da entidade recebido pelos assinantes do Subscribe
+type EntityEvent int
+
+const (
+	BeforeAppend EntityEvent = iota
+	AfterAppend
+	BeforeReplace
+	AfterReplace
+	BeforeRemove
+	AfterRemove
+	AfterCommit
+)
+
+func (e EntityEvent) String() string {
+	switch e {
+	case BeforeAppend:
+		return "before-append"
+	case AfterAppend:
+		return "after-append"
+	case BeforeReplace:
+		return "before-replace"
+	case AfterReplace:
+		return "after-replace"
+	case BeforeRemove:
+		return "before-remove"
+	case AfterRemove:
+		return "after-remove"
+	case AfterCommit:
+		return "after-commit"
+	}
+	return "unknown"
+}
+
+// SubscribeOption configura a assinatura do evento
+type SubscribeOption func(s *subscription)
+
+// Async executa o assinante em uma goroutine, com uma cópia da entidade e um contexto
+// que não é cancelado com o contexto da operação. O erro do assinante é entregue ao
+// OnSubscriberError e não interrompe a operação.
+func Async() SubscribeOption {
+	return func(s *subscription) {
+		s.async = true
+	}
+}
+
+// OnSubscriberError define a função que recebe os erros que não são retornados pela
+// operação: erros dos assinantes assíncronos e do AfterCommit. Nas assinaturas do
+// AfterCommit, recebe também o erro do AfterCommit (Triggable) da entidade. Sem ela,
+// estes erros são descartados.
+func OnSubscriberError(f func(ctx context.Context, event EntityEvent, err error)) SubscribeOption {
+	return func(s *subscription) {
+		s.onError = f
+	}
+}
+
+type subscription struct {
+	event   EntityEvent
+	handler func(ctx context.Context, e any) error
+	clone   func(e any) (any, func(), error)
+	async   bool
+	onError func(ctx context.Context, event EntityEvent, err error)
+}
+
+var (
+	subscriptions      = make(map[reflect.Type][]*subscription)
+	subscriptionsMutex sync.RWMutex
+)
+
+// Subscribe assina o evento das entidades do tipo T e retorna a função que cancela a
+// assinatura. Os assinantes são executados na ordem de assinatura, após o event handler
+// (Triggable) da entidade:
+//
+//   - BeforeAppend, BeforeReplace e BeforeRemove: o erro interrompe a operação antes da
+//     gravação e é retornado por ela
+//   - AfterAppend, AfterReplace e AfterRemove: o erro é retornado pela operação. Dentro
+//     de uma transação, a entidade ainda não foi confirmada.
+//   - AfterCommit: executado no Commit da transação em que a entidade foi gravada, ou logo
+//     após a operação fora de uma transação. A gravação já foi confirmada, portanto o erro
+//     é entregue ao OnSubscriberError.
+//
+// Com a opção Async os erros de todos os eventos são entregues ao OnSubscriberError.
+func Subscribe[T any](event EntityEvent, handler func(ctx context.Context, e *T) error, options ...SubscribeOption) (unsubscribe func()) {
+	s := &subscription{
+		event: event,
+		handler: func(ctx context.Context, e any) error {
+			return handler(ctx, e.(*T))
+		},
+		clone: func(e any) (any, func(), error) {
+			c := Use[T]()
+			w := any(c).(Workarea[T])
+			if err := w.instance().copyFrom(e.(*T)); err != nil {
+				w.Close()
+				return nil, nil, err
+			}
+			return c, w.Close, nil
+		},
+	}
+	for _, o := range options {
+		o(s)
+	}
+
+	t := reflect.TypeOf((*T)(nil))
+
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	subscriptions[t] = append(subscriptions[t], s)
+
+	return func() {
+		subscriptionsMutex.Lock()
+		defer subscriptionsMutex.Unlock()
+
+		subscriptions[t] = slices.DeleteFunc(subscriptions[t], func(v *subscription) bool {
+			return v == s
+		})
+	}
+}
+
+// publish executa os assinantes do evento da entidade
+func publish(ctx context.Context, event EntityEvent, e any) error {
+	subscriptionsMutex.RLock()
+	subs := slices.Clone(subscriptions[reflect.TypeOf(e)])
+	subscriptionsMutex.RUnlock()
+
+	for _, s := range subs {
+		if s.event != event {
+			continue
+		}
+
+		if s.async {
+			s.dispatch(ctx, e)
+			continue
+		}
+
+		if err := s.handler(ctx, e); err != nil {
+			if event == AfterCommit {
+				s.report(ctx, err)
+				continue
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// publishCommitted executa os assinantes do AfterCommit da entidade
+func publishCommitted(ctx context.Context, e any) {
+	// os erros do AfterCommit são entregues ao OnSubscriberError
+	_ = publish(ctx, AfterCommit, e)
+}
+
+// reportCommitError entrega o erro do AfterCommit (Triggable) da entidade aos
+// OnSubscriberError das assinaturas do AfterCommit
+func reportCommitError(ctx context.Context, e any, err error) {
+	subscriptionsMutex.RLock()
+	subs := slices.Clone(subscriptions[reflect.TypeOf(e)])
+	subscriptionsMutex.RUnlock()
+
+	for _, s := range subs {
+		if s.event == AfterCommit {
+			s.report(ctx, err)
+		}
+	}
+}
+
+// dispatch executa o assinante assíncrono com uma cópia da entidade, pois a entidade
+// pode ser alterada ou devolvida ao pool antes da execução
+func (s *subscription) dispatch(ctx context.Context, e any) {
+	ctx = context.WithoutCancel(ctx)
+
+	c, release, err := s.clone(e)
+	if err != nil {
+		s.report(ctx, err)
+		return
+	}
+
+	go func() {
+		defer release()
+
+		if err := s.handler(ctx, c); err != nil {
+			s.report(ctx, err)
+		}
+	}()
+}
+
+func (s *subscription) report(ctx context.Context, err error) {
+	if s.onError != nil {
+		s.onError(ctx, s.event, err)
+	}
+}
@@ -0,0 +1,248 @@
+package rdd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dopsilva/rdd/engine"
+	"github.com/dopsilva/rdd/field"
+)
+
+func TestSubscribe(t *testing.T) {
+	db, err := Connect(engine.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.CreateTable(schemaOf[Moeda](), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	errBloqueada := errors.New("moeda bloqueada")
+	events := make([]string, 0)
+
+	defer Subscribe(BeforeAppend, func(ctx context.Context, m *Moeda) error {
+		if m.Codigo.Get() == "XXX" {
+			return errBloqueada
+		}
+		return nil
+	})()
+	defer Subscribe(AfterAppend, func(ctx context.Context, m *Moeda) error {
+		events = append(events, "append "+m.Codigo.Get())
+		return nil
+	})()
+
+	failures := make([]error, 0)
+	defer Subscribe(AfterCommit, func(ctx context.Context, m *Moeda) error {
+		events = append(events, "commit "+m.Codigo.Get())
+		return errors.New("falha após o commit")
+	}, OnSubscriberError(func(ctx context.Context, event EntityEvent, err error) {
+		failures = append(failures, err)
+	}))()
+
+	replaced := make(chan string, 1)
+	defer Subscribe(AfterReplace, func(ctx context.Context, m *Moeda) error {
+		replaced <- m.Nome.Get()
+		return nil
+	}, Async())()
+
+	newMoeda := func(codigo string) *Moeda {
+		m := Use[Moeda]()
+		m.Codigo.Set(codigo)
+		m.Simbolo.Set(codigo)
+		m.Nome.Set(codigo)
+		return m
+	}
+
+	// o erro do Before interrompe a operação
+	x := newMoeda("XXX")
+	defer x.Close()
+	if err := x.Append(testContext, db); !errors.Is(err, errBloqueada) {
+		t.Fatalf("esperado %v obtido %v", errBloqueada, err)
+	}
+
+	// dentro da transação o AfterCommit é executado somente no Commit
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	brl := newMoeda("BRL")
+	defer brl.Close()
+	if err := brl.Append(testContext, tx); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("eventos inesperados antes do commit %v", events)
+	}
+	if err := tx.Commit(testContext); err != nil {
+		t.Fatal(err)
+	}
+
+	// fora da transação o AfterCommit é executado após a operação
+	usd := newMoeda("USD")
+	defer usd.Close()
+	if err := usd.Append(testContext, db); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"append BRL", "commit BRL", "append USD", "commit USD"}
+	if len(events) != len(expected) {
+		t.Fatalf("esperado %v obtido %v", expected, events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("esperado %v obtido %v", expected, events)
+		}
+	}
+	if len(failures) != 2 {
+		t.Fatalf("esperado 2 erros do AfterCommit obtido %v", failures)
+	}
+
+	// o assinante assíncrono recebe uma cópia da entidade
+	usd.Nome.Set("Dólar")
+	if err := usd.Replace(testContext, db); err != nil {
+		t.Fatal(err)
+	}
+	usd.Nome.Set("alterado")
+
+	select {
+	case nome := <-replaced:
+		if nome != "Dólar" {
+			t.Fatalf("esperado Dólar obtido %s", nome)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("assinante assíncrono não executado")
+	}
+}
+
+func TestSavepointEvents(t *testing.T) {
+	db, err := Connect(engine.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.CreateTable(schemaOf[Moeda](), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	committed := make([]string, 0)
+	defer Subscribe(AfterCommit, func(ctx context.Context, m *Moeda) error {
+		committed = append(committed, m.Codigo.Get())
+		return nil
+	})()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codigos := []string{"BRL", "USD", "EUR"}
+	moedas := make([]*Moeda, len(codigos))
+	for i, c := range codigos {
+		moedas[i] = Use[Moeda]()
+		defer moedas[i].Close()
+		moedas[i].Codigo.Set(c)
+		moedas[i].Nome.Set(c)
+		moedas[i].Simbolo.Set(c)
+
+		// BRL na transação, USD no savepoint desfeito e EUR no savepoint confirmado
+		var sp Database = tx
+		if i > 0 {
+			if sp, err = tx.Begin(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := moedas[i].Append(testContext, sp); err != nil {
+			t.Fatal(err)
+		}
+		switch i {
+		case 1:
+			err = sp.Rollback()
+		case 2:
+			err = sp.Commit(testContext)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tx.Commit(testContext); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(committed) != 2 || committed[0] != "BRL" || committed[1] != "EUR" {
+		t.Fatalf("esperado AfterCommit de BRL e EUR obtido %v", committed)
+	}
+	if n, err := Count[Moeda](db); err != nil || n != 2 {
+		t.Fatalf("esperado 2 moedas obtido %d (%v)", n, err)
+	}
+}
+
+var errCotacao = errors.New("falha no AfterCommit da cotação")
+
+type Cotacao struct {
+	Workarea[Cotacao] `rdd-table:"cotacoes"`
+
+	Moeda field.Field[string]  `rdd-column:"moeda" rdd-primary-key:"true"`
+	Valor field.Field[float64] `rdd-column:"valor"`
+}
+
+func (c *Cotacao) AfterCommit(params EventParameters) error {
+	return errCotacao
+}
+
+func TestAfterCommitOnce(t *testing.T) {
+	db, err := Connect(engine.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	c := Use[Cotacao]()
+	if err := db.CreateTable(c.Schema(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	committed := make([]string, 0)
+	failures := make([]error, 0)
+	defer Subscribe(AfterCommit, func(ctx context.Context, c *Cotacao) error {
+		committed = append(committed, fmt.Sprintf("%s %v", c.Moeda.Get(), c.Valor.Get()))
+		return nil
+	}, OnSubscriberError(func(ctx context.Context, event EntityEvent, err error) {
+		failures = append(failures, err)
+	}))()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Moeda.Set("USD")
+	c.Valor.Set(5)
+	if err := c.Append(testContext, tx); err != nil {
+		t.Fatal(err)
+	}
+	c.Valor.Set(6)
+	if err := c.Replace(testContext, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	// a entidade fechada antes do commit retorna ao pool somente após o commit
+	c.Close()
+
+	if err := tx.Commit(testContext); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(committed) != 1 || committed[0] != "USD 6" {
+		t.Fatalf("esperado um AfterCommit de USD 6 obtido %v", committed)
+	}
+	if len(failures) != 1 || !errors.Is(failures[0], errCotacao) {
+		t.Fatalf("esperado o erro do AfterCommit da entidade obtido %v", failures)
+	}
+}
@@ -0,0 +1,195 @@
+package field
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/dopsilva/rdd/engine"
+	"github.com/dopsilva/rdd/schema"
+)
+
+// Assignable é implementada pelos campos que aceitam valores de outros tipos.
+// É utilizada no Load da workarea.
+type Assignable interface {
+	Assign(v any) error
+}
+
+// TypeOptions descreve um tipo definido pelo usuário
+type TypeOptions[T any] struct {
+	// Name é o nome do tipo no schema. O padrão é o nome qualificado do tipo, ex.: "doc.CPF"
+	Name string
+	// Equal compara dois valores do tipo. Se não informado é utilizado o método
+	// Equal(T) bool do tipo, o operador == ou o reflect.DeepEqual.
+	Equal func(a, b T) bool
+	// Columns é o tipo da coluna para cada engine
+	Columns map[engine.Engine]string
+}
+
+type customType struct {
+	name    string
+	equal   func(a, b any) bool
+	columns map[engine.Engine]string
+}
+
+var (
+	customTypes       = make(map[reflect.Type]*customType)
+	customTypesByName = make(map[string]*customType)
+	customTypesMutex  sync.RWMutex
+)
+
+// RegisterType registra o tipo definido pelo usuário. O registro deve ser feito antes
+// do registro das entidades que utilizam o tipo.
+func RegisterType[T any](options TypeOptions[T]) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	ct := &customType{name: options.Name, columns: options.Columns}
+	if ct.name == "" {
+		ct.name = t.String()
+	}
+	if options.Equal != nil {
+		ct.equal = func(a, b any) bool { return options.Equal(a.(T), b.(T)) }
+	}
+
+	customTypesMutex.Lock()
+	defer customTypesMutex.Unlock()
+
+	customTypes[t] = ct
+	customTypesByName[ct.name] = ct
+}
+
+// ColumnType retorna o tipo da coluna do tipo registrado para a engine
+func ColumnType(name string, e engine.Engine) (string, bool) {
+	customTypesMutex.RLock()
+	defer customTypesMutex.RUnlock()
+
+	if ct, ok := customTypesByName[name]; ok {
+		c, ok := ct.columns[e]
+		return c, ok
+	}
+	return "", false
+}
+
+func lookupType(t reflect.Type) (*customType, bool) {
+	customTypesMutex.RLock()
+	defer customTypesMutex.RUnlock()
+
+	ct, ok := customTypes[t]
+	return ct, ok
+}
+
+// Custom é o campo para tipos definidos pelo usuário. O tipo deve implementar
+// driver.Valuer e sql.Scanner (com receiver ponteiro).
+type Custom[T any] struct {
+	schema schema.Field
+	value  T
+	old    T
+}
+
+// Name obtém o nome do campo
+func (f *Custom[T]) Name() string {
+	return f.schema.Name
+}
+
+// Bind associa o schema ao campo
+func (f *Custom[T]) Bind(s schema.Field) {
+	f.schema = s
+}
+
+// Schema retorna o schema do campo
+func (f *Custom[T]) Schema() schema.Field {
+	return f.schema
+}
+
+// Get obtém o valor do campo
+func (f *Custom[T]) Get() T {
+	return f.value
+}
+
+// Set define o valor do campo
+func (f *Custom[T]) Set(value T) {
+	f.value = value
+}
+
+// Assign define o valor do campo a partir do próprio tipo ou de um valor do driver
+func (f *Custom[T]) Assign(v any) error {
+	if tv, ok := v.(T); ok {
+		f.Set(tv)
+		return nil
+	}
+	return f.Scan(v)
+}
+
+// Changed verifica se houve alteração no campo
+func (f *Custom[T]) Changed() bool {
+	return !equalCustom(f.value, f.old)
+}
+
+func equalCustom[T any](a, b T) bool {
+	if ct, ok := lookupType(reflect.TypeOf((*T)(nil)).Elem()); ok && ct.equal != nil {
+		return ct.equal(a, b)
+	}
+	if e, ok := any(a).(interface{ Equal(T) bool }); ok {
+		return e.Equal(b)
+	}
+	if t := reflect.TypeOf((*T)(nil)).Elem(); t.Comparable() && t.Kind() != reflect.Interface {
+		return any(a) == any(b)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// Zero retorna o valor zero do tipo de dado do campo
+func (f *Custom[T]) Zero() T {
+	var i T
+	return i
+}
+
+// Reset zera os valores
+func (f *Custom[T]) Reset() {
+	var zero T = f.Zero()
+	f.value = zero
+	f.old = zero
+}
+
+// Empty retorna se o conteudo está vazio
+func (f *Custom[T]) Empty() bool {
+	return reflect.ValueOf(&f.value).Elem().IsZero()
+}
+
+// Type retorna o tipo nativo do dado
+func (f *Custom[T]) Type() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// Freeze resfria o campo tornando iguais o antigo e o atual valor
+func (f *Custom[T]) Freeze() {
+	f.old = f.value
+}
+
+// Value implementa a interface sql.Valuer
+func (f *Custom[T]) Value() (driver.Value, error) {
+	if v, ok := any(f.value).(driver.Valuer); ok {
+		return v.Value()
+	}
+	if v, ok := any(&f.value).(driver.Valuer); ok {
+		return v.Value()
+	}
+	return nil, fmt.Errorf("field: %T does not implement driver.Valuer", f.value)
+}
+
+// Scan implementa a interface sql.Scanner
+func (f *Custom[T]) Scan(value any) error {
+	s, ok := any(&f.value).(sql.Scanner)
+	if !ok {
+		return fmt.Errorf("field: %T does not implement sql.Scanner", f.value)
+	}
+	if err := s.Scan(value); err != nil {
+		if f.schema.Name != "" {
+			return fmt.Errorf("%w (column %s)", err, f.schema.Name)
+		}
+		return err
+	}
+	return nil
+}
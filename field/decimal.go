@@ -0,0 +1,150 @@
+package field
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal é um número decimal exato, adequado para valores monetários.
+// O valor é representado por um coeficiente inteiro e a quantidade de casas decimais.
+type Decimal struct {
+	coef  *big.Int // nil representa zero
+	scale int32
+}
+
+// NewDecimal cria o decimal coef * 10^-scale. Ex.: NewDecimal(1050, 2) = 10.50
+func NewDecimal(coef int64, scale int32) Decimal {
+	return Decimal{coef: big.NewInt(coef), scale: scale}
+}
+
+// ParseDecimal converte o texto para decimal
+func ParseDecimal(s string) (Decimal, error) {
+	v := strings.TrimSpace(s)
+
+	intp, frac, _ := strings.Cut(v, ".")
+	digits := intp + frac
+
+	coef, ok := new(big.Int).SetString(digits, 10)
+	if !ok || intp == "" || intp == "-" || intp == "+" || strings.ContainsAny(frac, "+-") {
+		return Decimal{}, fmt.Errorf("field: invalid decimal %q", s)
+	}
+
+	return Decimal{coef: coef, scale: int32(len(frac))}, nil
+}
+
+// MustParseDecimal converte o texto para decimal, gerando panic em caso de erro
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) coefficient() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// Scale retorna a quantidade de casas decimais
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// IsZero verifica se o valor é zero
+func (d Decimal) IsZero() bool {
+	return d.coefficient().Sign() == 0
+}
+
+// Cmp compara os decimais, retornando -1, 0 ou 1. A escala não é considerada: 1.0 == 1.00
+func (d Decimal) Cmp(o Decimal) int {
+	a, b := d.coefficient(), o.coefficient()
+
+	switch {
+	case d.scale < o.scale:
+		a = rescale(a, o.scale-d.scale)
+	case d.scale > o.scale:
+		b = rescale(b, d.scale-o.scale)
+	}
+
+	return a.Cmp(b)
+}
+
+func rescale(v *big.Int, n int32) *big.Int {
+	m := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+	return m.Mul(m, v)
+}
+
+// String retorna o decimal no formato texto, preservando as casas decimais
+func (d Decimal) String() string {
+	s := d.coefficient().String()
+	if d.scale <= 0 {
+		return s
+	}
+
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	if n := int(d.scale) + 1 - len(s); n > 0 {
+		s = strings.Repeat("0", n) + s
+	}
+	s = s[:len(s)-int(d.scale)] + "." + s[len(s)-int(d.scale):]
+
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// Float64 retorna o valor aproximado em ponto flutuante
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// Value implementa a interface driver.Valuer. O valor é enviado como texto para não perder precisão.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implementa a interface sql.Scanner
+func (d *Decimal) Scan(src any) error {
+	var err error
+
+	switch v := src.(type) {
+	case string:
+		*d, err = ParseDecimal(v)
+	case []byte:
+		*d, err = ParseDecimal(string(v))
+	case int64:
+		*d = NewDecimal(v, 0)
+	case float64:
+		*d, err = ParseDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+	case nil:
+		err = fmt.Errorf("field: cannot scan NULL into %T", *d)
+	default:
+		err = conversionError(src, "Decimal")
+	}
+
+	return err
+}
+
+// MarshalJSON representa o decimal como número json
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON aceita o decimal como número ou texto json
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	v, err := ParseDecimal(strings.Trim(string(b), `"`))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
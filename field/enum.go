@@ -0,0 +1,51 @@
+package field
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidEnum = errors.New("field: invalid enum value")
+
+// Enum é o campo texto restrito aos valores declarados na tag rdd-enum.
+// Ex.: Situacao field.Enum `rdd-column:"situacao" rdd-enum:"active,blocked,deleted"`
+type Enum struct {
+	Field[string]
+}
+
+// Allowed retorna os valores aceitos pelo campo
+func (f *Enum) Allowed() []string {
+	return f.schema.Enum
+}
+
+// Valid verifica se o valor é aceito pelo campo. O texto vazio é aceito somente nos
+// campos nullable, em que representa o campo sem valor.
+func (f *Enum) Valid(value string) bool {
+	if len(f.schema.Enum) == 0 {
+		return true
+	}
+	if value == "" {
+		return f.schema.Nullable
+	}
+	return slices.Contains(f.schema.Enum, value)
+}
+
+// Set define o valor do campo, retornando ErrInvalidEnum se o valor não for aceito
+func (f *Enum) Set(value string) error {
+	if !f.Valid(value) {
+		return fmt.Errorf("%w %q for %s: allowed [%s]", ErrInvalidEnum, value, f.schema.Name, strings.Join(f.schema.Enum, ", "))
+	}
+	f.Field.Set(value)
+	return nil
+}
+
+// Assign define o valor do campo validando os valores aceitos
+func (f *Enum) Assign(v any) error {
+	s, err := asString(v)
+	if err != nil {
+		return err
+	}
+	return f.Set(s)
+}
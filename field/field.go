@@ -1,6 +1,7 @@
 package field
 
 import (
+	"bytes"
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/dopsilva/rdd/schema"
+	"github.com/google/uuid"
 )
 
 type Typed interface {
@@ -30,6 +32,12 @@ type Valuer interface {
 	Value() (driver.Value, error)
 }
 
+// Bindable é implementada pelos campos que conhecem o seu schema
+type Bindable interface {
+	Bind(schema.Field)
+	Schema() schema.Field
+}
+
 type Fieldable interface {
 	Typed
 	Changeable
@@ -38,8 +46,9 @@ type Fieldable interface {
 	Valuer
 }
 
-type FieldConstraint[T comparable] interface {
-	string | int64 | bool | float64 | time.Time | sql.NullString | sql.NullInt64 | sql.NullBool | sql.NullFloat64 | sql.NullTime
+type FieldConstraint[T any] interface {
+	string | int64 | int32 | int16 | bool | float64 | []byte | time.Time | Decimal | uuid.UUID | JSON |
+		sql.NullString | sql.NullInt64 | sql.NullInt32 | sql.NullInt16 | sql.NullBool | sql.NullFloat64 | sql.NullTime
 }
 
 type Field[T FieldConstraint[T]] struct {
@@ -59,6 +68,16 @@ func (f *Field[T]) Name() string {
 	return f.schema.Name
 }
 
+// Bind associa o schema ao campo
+func (f *Field[T]) Bind(s schema.Field) {
+	f.schema = s
+}
+
+// Schema retorna o schema do campo
+func (f *Field[T]) Schema() schema.Field {
+	return f.schema
+}
+
 // Get obtém o valor do campo
 func (f *Field[T]) Get() T {
 	return f.value
@@ -69,52 +88,86 @@ func (f *Field[T]) Set(value T) {
 	f.value = value
 }
 
+// Assign define o valor do campo convertendo o valor informado para o tipo do campo
+func (f *Field[T]) Assign(v any) error {
+	if tv, ok := v.(T); ok {
+		f.Set(tv)
+		return nil
+	}
+	return f.Scan(v)
+}
+
 // Changed verifica se houve alteração no campo
 func (f *Field[T]) Changed() bool {
-	switch f.Type().Kind() {
-	case reflect.String, reflect.Int, reflect.Int64, reflect.Float64, reflect.Bool:
-		return f.value != f.old
+	return !equal(f.value, f.old)
+}
+
+// equal compara os valores de acordo com o tipo de dado
+func equal[T any](a, b T) bool {
+	switch reflect.TypeOf(a).Kind() {
+	case reflect.String, reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Float64, reflect.Bool:
+		return any(a) == any(b)
 	default:
-		switch any(f.value).(type) {
+		switch any(a).(type) {
+		case []byte:
+			return bytes.Equal(any(a).([]byte), any(b).([]byte))
+		case JSON:
+			return bytes.Equal(any(a).(JSON), any(b).(JSON))
+		case Decimal:
+			return (any(a).(Decimal)).Cmp(any(b).(Decimal)) == 0
+		case uuid.UUID:
+			return any(a) == any(b)
 		case time.Time:
-			if (any(f.value).(time.Time)).Compare(any(f.old).(time.Time)) != 0 {
-				return true
+			if (any(a).(time.Time)).Compare(any(b).(time.Time)) != 0 {
+				return false
 			}
 		case sql.NullString:
-			v := any(f.value).(sql.NullString)
-			o := any(f.old).(sql.NullString)
+			v := any(a).(sql.NullString)
+			o := any(b).(sql.NullString)
 			if v.Valid != o.Valid || v.String != o.String {
-				return true
+				return false
 			}
 		case sql.NullInt64:
-			v := any(f.value).(sql.NullInt64)
-			o := any(f.old).(sql.NullInt64)
+			v := any(a).(sql.NullInt64)
+			o := any(b).(sql.NullInt64)
 			if v.Valid != o.Valid || v.Int64 != o.Int64 {
-				return true
+				return false
 			}
 		case sql.NullBool:
-			v := any(f.value).(sql.NullBool)
-			o := any(f.old).(sql.NullBool)
+			v := any(a).(sql.NullBool)
+			o := any(b).(sql.NullBool)
 			if v.Valid != o.Valid || v.Bool != o.Bool {
-				return true
+				return false
+			}
+		case sql.NullInt32:
+			v := any(a).(sql.NullInt32)
+			o := any(b).(sql.NullInt32)
+			if v.Valid != o.Valid || v.Int32 != o.Int32 {
+				return false
+			}
+		case sql.NullInt16:
+			v := any(a).(sql.NullInt16)
+			o := any(b).(sql.NullInt16)
+			if v.Valid != o.Valid || v.Int16 != o.Int16 {
+				return false
 			}
 		case sql.NullFloat64:
-			v := any(f.value).(sql.NullFloat64)
-			o := any(f.old).(sql.NullFloat64)
+			v := any(a).(sql.NullFloat64)
+			o := any(b).(sql.NullFloat64)
 			if v.Valid != o.Valid || v.Float64 != o.Float64 {
-				return true
+				return false
 			}
 		case sql.NullTime:
-			v := any(f.value).(sql.NullTime)
-			o := any(f.old).(sql.NullTime)
+			v := any(a).(sql.NullTime)
+			o := any(b).(sql.NullTime)
 			if v.Valid != o.Valid || v.Time.Compare(o.Time) != 0 {
-				return true
+				return false
 			}
 		default:
-			panic(fmt.Sprintf("unsupported type %T", f.value))
+			panic(fmt.Sprintf("unsupported type %T", a))
 		}
 	}
-	return false
+	return true
 }
 
 // Zero retorna o valor zero do tipo de dado do campo
@@ -141,8 +194,20 @@ func (f *Field[T]) Empty() bool {
 		return v == nil || *v == ""
 	case int64:
 		return v == 0
+	case int32:
+		return v == 0
+	case int16:
+		return v == 0
 	case float64:
 		return v == 0
+	case []byte:
+		return len(v) == 0
+	case JSON:
+		return len(v) == 0
+	case Decimal:
+		return v.IsZero()
+	case uuid.UUID:
+		return v == uuid.Nil
 	case time.Time:
 		return v.IsZero()
 	}
@@ -154,6 +219,17 @@ func (f *Field[T]) Type() reflect.Type {
 	return reflect.TypeOf(f.value)
 }
 
+// TypeName retorna o nome do tipo utilizado no schema
+func TypeName(t reflect.Type) string {
+	if ct, ok := lookupType(t); ok {
+		return ct.name
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 && t.Name() == "" {
+		return "bytes"
+	}
+	return t.Name()
+}
+
 // Freeze resfria o campo tornando iguais o antigo e o atual valor
 func (f *Field[T]) Freeze() {
 	f.old = f.value
@@ -161,12 +237,33 @@ func (f *Field[T]) Freeze() {
 
 // Value implementa a interface sql.Valuer
 func (f *Field[T]) Value() (driver.Value, error) {
-	return f.value, nil
+	return value(f.value)
+}
+
+// value converte o valor para um tipo aceito pelo driver
+func value[T any](v T) (driver.Value, error) {
+	switch v := any(v).(type) {
+	case int32:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case driver.Valuer:
+		// sql.Null*, Decimal, uuid.UUID e JSON
+		return v.Value()
+	}
+	return v, nil
 }
 
-// Value implementa a interface sql.Scanner
+// Scan implementa a interface sql.Scanner, convertendo o valor retornado pelo driver
 func (f *Field[T]) Scan(value any) error {
-	f.Set(value.(T))
+	v, err := convert[T](value)
+	if err != nil {
+		if f.schema.Name != "" {
+			return fmt.Errorf("%w (column %s)", err, f.schema.Name)
+		}
+		return err
+	}
+	f.Set(v)
 	return nil
 }
 
@@ -0,0 +1,179 @@
+package field
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestScan(t *testing.T) {
+	ts := time.Date(2024, 3, 10, 14, 30, 0, 0, time.UTC)
+
+	var s Field[string]
+	var i Field[int64]
+	var f Field[float64]
+	var b Field[bool]
+	var tm Field[time.Time]
+	var ns Field[sql.NullString]
+	var nt Field[sql.NullTime]
+	var nb Field[sql.NullBool]
+
+	var tests = []struct {
+		name string
+		scan func(any) error
+		src  any
+		ok   func() bool
+	}{
+		{"string from bytes", s.Scan, []byte("abc"), func() bool { return s.Get() == "abc" }},
+		{"int64 from string", i.Scan, "42", func() bool { return i.Get() == 42 }},
+		{"float64 from int64", f.Scan, int64(3), func() bool { return f.Get() == 3 }},
+		{"bool from int64", b.Scan, int64(1), func() bool { return b.Get() }},
+		{"time from text", tm.Scan, "2024-03-10 14:30:00", func() bool { return tm.Get().Equal(ts) }},
+		{"time from text with zone", tm.Scan, "2024-03-10 11:30:00-03:00", func() bool { return tm.Get().Equal(ts) }},
+		{"null string", ns.Scan, nil, func() bool { return !ns.Get().Valid }},
+		{"null string from bytes", ns.Scan, []byte("x"), func() bool { return ns.Get().Valid && ns.Get().String == "x" }},
+		{"null time from text", nt.Scan, "2024-03-10T14:30:00Z", func() bool { return nt.Get().Valid && nt.Get().Time.Equal(ts) }},
+		{"null time", nt.Scan, nil, func() bool { return !nt.Get().Valid }},
+		{"null bool from int64", nb.Scan, int64(0), func() bool { return nb.Get().Valid && !nb.Get().Bool }},
+	}
+
+	for _, test := range tests {
+		if err := test.scan(test.src); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !test.ok() {
+			t.Fatalf("%s: valor inesperado", test.name)
+		}
+	}
+}
+
+func TestScanErrors(t *testing.T) {
+	var s Field[string]
+	var i Field[int64]
+	var b Field[bool]
+	var tm Field[time.Time]
+
+	var tests = []struct {
+		name string
+		scan func(any) error
+		src  any
+	}{
+		{"null into string", s.Scan, nil},
+		{"text into int64", i.Scan, "abc"},
+		{"fraction into int64", i.Scan, 1.5},
+		{"integer into bool", b.Scan, int64(2)},
+		{"text into time", tm.Scan, "ontem"},
+	}
+
+	for _, test := range tests {
+		if err := test.scan(test.src); err == nil {
+			t.Fatalf("%s: esperado erro", test.name)
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out string
+	}{
+		{"10.50", "10.50"},
+		{"-0.05", "-0.05"},
+		{"123456789012345678901234.5", "123456789012345678901234.5"},
+		{"7", "7"},
+	}
+
+	for _, test := range tests {
+		d, err := ParseDecimal(test.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.String() != test.out {
+			t.Fatalf("esperado %s obtido %s", test.out, d.String())
+		}
+	}
+
+	if MustParseDecimal("1.0").Cmp(MustParseDecimal("1.00")) != 0 {
+		t.Fatal("esperado 1.0 == 1.00")
+	}
+	if NewDecimal(1050, 2).Cmp(MustParseDecimal("10.49")) != 1 {
+		t.Fatal("esperado 10.50 > 10.49")
+	}
+	if _, err := ParseDecimal("1.2.3"); err == nil {
+		t.Fatal("esperado erro")
+	}
+}
+
+func TestChangedTypes(t *testing.T) {
+	var b Field[[]byte]
+	var d Field[Decimal]
+	var j Field[JSON]
+	var u Field[uuid.UUID]
+	var i Field[int32]
+
+	b.Set([]byte("abc"))
+	b.Freeze()
+	b.Set([]byte("abc"))
+	if b.Changed() {
+		t.Fatal("[]byte: não esperado alteração")
+	}
+
+	d.Set(MustParseDecimal("1.0"))
+	d.Freeze()
+	d.Set(MustParseDecimal("1.00"))
+	if d.Changed() {
+		t.Fatal("Decimal: não esperado alteração")
+	}
+
+	j.Set(JSON(`{"a":1}`))
+	if !j.Changed() {
+		t.Fatal("JSON: esperado alteração")
+	}
+
+	u.Set(uuid.New())
+	if !u.Changed() {
+		t.Fatal("UUID: esperado alteração")
+	}
+
+	if err := i.Scan(int64(1 << 40)); err == nil {
+		t.Fatal("int32: esperado erro de overflow")
+	}
+	if err := i.Scan(int64(12)); err != nil || i.Get() != 12 {
+		t.Fatalf("int32: esperado 12 obtido %d (%v)", i.Get(), err)
+	}
+	if v, _ := i.Value(); v != int64(12) {
+		t.Fatalf("int32: esperado valor int64 obtido %T", v)
+	}
+}
+
+func TestNullable(t *testing.T) {
+	var n Nullable[string]
+
+	if v, _ := n.Value(); v != nil {
+		t.Fatalf("esperado nulo obtido %v", v)
+	}
+
+	n.Set("")
+	if !n.Changed() || n.IsNull() {
+		t.Fatal("esperado valor vazio não nulo")
+	}
+
+	n.Freeze()
+	n.SetNull()
+	if !n.Changed() {
+		t.Fatal("esperado alteração para nulo")
+	}
+
+	if err := n.Scan([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := n.Get(); !ok || v != "abc" {
+		t.Fatalf("esperado abc obtido %q", v)
+	}
+
+	if err := n.Scan(nil); err != nil || !n.IsNull() {
+		t.Fatalf("esperado nulo (%v)", err)
+	}
+}
@@ -0,0 +1,64 @@
+package field
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// JSON é um documento json armazenado no campo
+type JSON []byte
+
+// NewJSON cria o documento a partir do valor informado
+func NewJSON(v any) (JSON, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return JSON(b), nil
+}
+
+// Unmarshal decodifica o documento para o valor informado
+func (j JSON) Unmarshal(v any) error {
+	return json.Unmarshal(j, v)
+}
+
+// Value implementa a interface driver.Valuer. O documento vazio é gravado como NULL.
+func (j JSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	if !json.Valid(j) {
+		return nil, fmt.Errorf("field: invalid json document")
+	}
+	return string(j), nil
+}
+
+// Scan implementa a interface sql.Scanner
+func (j *JSON) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*j = nil
+	case string:
+		*j = JSON(v)
+	case []byte:
+		*j = append(JSON(nil), v...)
+	default:
+		return conversionError(src, "JSON")
+	}
+	return nil
+}
+
+// MarshalJSON retorna o próprio documento
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+// UnmarshalJSON armazena uma cópia do documento
+func (j *JSON) UnmarshalJSON(b []byte) error {
+	*j = append(JSON(nil), b...)
+	return nil
+}
@@ -0,0 +1,158 @@
+package field
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+
+	"github.com/dopsilva/rdd/schema"
+)
+
+// Optional é implementada pelos campos que aceitam nulo. O schema desses campos
+// é nullable, a menos que a tag rdd-nullable informe o contrário.
+type Optional interface {
+	IsNull() bool
+	SetNull()
+}
+
+// Nullable é o campo que aceita nulo sem a necessidade dos tipos sql.Null*
+type Nullable[T FieldConstraint[T]] struct {
+	schema   schema.Field
+	value    T
+	valid    bool
+	old      T
+	oldValid bool
+}
+
+// Name obtém o nome do campo
+func (f *Nullable[T]) Name() string {
+	return f.schema.Name
+}
+
+// Bind associa o schema ao campo
+func (f *Nullable[T]) Bind(s schema.Field) {
+	f.schema = s
+}
+
+// Schema retorna o schema do campo
+func (f *Nullable[T]) Schema() schema.Field {
+	return f.schema
+}
+
+// Get obtém o valor do campo e se ele não é nulo
+func (f *Nullable[T]) Get() (T, bool) {
+	return f.value, f.valid
+}
+
+// Set define o valor do campo
+func (f *Nullable[T]) Set(value T) {
+	f.value = value
+	f.valid = true
+}
+
+// SetNull define o campo como nulo
+func (f *Nullable[T]) SetNull() {
+	f.value = f.Zero()
+	f.valid = false
+}
+
+// IsNull verifica se o campo é nulo
+func (f *Nullable[T]) IsNull() bool {
+	return !f.valid
+}
+
+// Assign define o valor do campo a partir de T, *T, sql.Null[T] ou de um valor do driver
+func (f *Nullable[T]) Assign(v any) error {
+	switch tv := v.(type) {
+	case nil:
+		f.SetNull()
+	case T:
+		f.Set(tv)
+	case *T:
+		if tv == nil {
+			f.SetNull()
+		} else {
+			f.Set(*tv)
+		}
+	case sql.Null[T]:
+		if tv.Valid {
+			f.Set(tv.V)
+		} else {
+			f.SetNull()
+		}
+	default:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				f.SetNull()
+				return nil
+			}
+			return f.Scan(rv.Elem().Interface())
+		}
+		return f.Scan(v)
+	}
+	return nil
+}
+
+// Changed verifica se houve alteração no campo
+func (f *Nullable[T]) Changed() bool {
+	if f.valid != f.oldValid {
+		return true
+	}
+	return f.valid && !equal(f.value, f.old)
+}
+
+// Zero retorna o valor zero do tipo de dado do campo
+func (f *Nullable[T]) Zero() T {
+	var i T
+	return i
+}
+
+// Reset zera os valores
+func (f *Nullable[T]) Reset() {
+	var zero T = f.Zero()
+	f.value, f.valid = zero, false
+	f.old, f.oldValid = zero, false
+}
+
+// Empty retorna se o campo é nulo
+func (f *Nullable[T]) Empty() bool {
+	return !f.valid
+}
+
+// Type retorna o tipo nativo do dado
+func (f *Nullable[T]) Type() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// Freeze resfria o campo tornando iguais o antigo e o atual valor
+func (f *Nullable[T]) Freeze() {
+	f.old, f.oldValid = f.value, f.valid
+}
+
+// Value implementa a interface sql.Valuer
+func (f *Nullable[T]) Value() (driver.Value, error) {
+	if !f.valid {
+		return nil, nil
+	}
+	return value(f.value)
+}
+
+// Scan implementa a interface sql.Scanner
+func (f *Nullable[T]) Scan(src any) error {
+	if src == nil {
+		f.SetNull()
+		return nil
+	}
+
+	v, err := convert[T](src)
+	if err != nil {
+		if f.schema.Name != "" {
+			return fmt.Errorf("%w (column %s)", err, f.schema.Name)
+		}
+		return err
+	}
+	f.Set(v)
+
+	return nil
+}
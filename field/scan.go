@@ -0,0 +1,227 @@
+package field
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// TimestampFormats são os formatos aceitos na conversão de texto para time.Time.
+// São os mesmos formatos utilizados pelo driver do sqlite.
+var TimestampFormats = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	time.RFC3339Nano,
+}
+
+// convert converte o valor retornado pelo driver para o tipo T
+func convert[T any](src any) (T, error) {
+	var dst T
+
+	// o driver pode reutilizar o buffer, por isso é feita uma cópia
+	if d, ok := any(&dst).(*[]byte); ok {
+		b, err := asBytes(src)
+		*d = b
+		return dst, err
+	}
+
+	if v, ok := src.(T); ok {
+		return v, nil
+	}
+
+	switch d := any(&dst).(type) {
+	case *sql.NullTime:
+		// o sql.NullTime não converte texto, comum no sqlite
+		if src == nil {
+			return dst, nil
+		}
+		t, err := asTime(src)
+		if err != nil {
+			return dst, err
+		}
+		*d = sql.NullTime{Time: t, Valid: true}
+		return dst, nil
+	case sql.Scanner:
+		if err := d.Scan(src); err != nil {
+			return dst, fmt.Errorf("field: cannot convert %T to %T: %w", src, dst, err)
+		}
+		return dst, nil
+	}
+
+	if src == nil {
+		return dst, fmt.Errorf("field: cannot scan NULL into %T", dst)
+	}
+
+	var v any
+	var err error
+
+	switch any(dst).(type) {
+	case string:
+		v, err = asString(src)
+	case int64:
+		v, err = asInt64(src)
+	case int32:
+		var i int64
+		if i, err = asInt64(src); err == nil {
+			if i > math.MaxInt32 || i < math.MinInt32 {
+				err = conversionError(src, "int32")
+			}
+			v = int32(i)
+		}
+	case int16:
+		var i int64
+		if i, err = asInt64(src); err == nil {
+			if i > math.MaxInt16 || i < math.MinInt16 {
+				err = conversionError(src, "int16")
+			}
+			v = int16(i)
+		}
+	case float64:
+		v, err = asFloat64(src)
+	case bool:
+		v, err = asBool(src)
+	case time.Time:
+		v, err = asTime(src)
+	default:
+		err = fmt.Errorf("field: unsupported type %T", dst)
+	}
+
+	if err != nil {
+		return dst, err
+	}
+
+	return v.(T), nil
+}
+
+func conversionError(src any, dst string) error {
+	return fmt.Errorf("field: cannot convert %T (%v) to %s", src, src, dst)
+}
+
+func asString(src any) (string, error) {
+	switch v := src.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	}
+	return "", conversionError(src, "string")
+}
+
+func asBytes(src any) ([]byte, error) {
+	switch v := src.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return append([]byte(nil), v...), nil
+	case string:
+		return []byte(v), nil
+	}
+	return nil, conversionError(src, "[]byte")
+}
+
+func asInt64(src any) (int64, error) {
+	switch v := src.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case float64:
+		if v != math.Trunc(v) || v > math.MaxInt64 || v < math.MinInt64 {
+			return 0, conversionError(src, "int64")
+		}
+		return int64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, conversionError(src, "int64")
+		}
+		return i, nil
+	case []byte:
+		return asInt64(string(v))
+	}
+	return 0, conversionError(src, "int64")
+}
+
+func asFloat64(src any) (float64, error) {
+	switch v := src.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, conversionError(src, "float64")
+		}
+		return f, nil
+	case []byte:
+		return asFloat64(string(v))
+	}
+	return 0, conversionError(src, "float64")
+}
+
+func asBool(src any) (bool, error) {
+	switch v := src.(type) {
+	case bool:
+		return v, nil
+	case int64:
+		switch v {
+		case 0:
+			return false, nil
+		case 1:
+			return true, nil
+		}
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err == nil {
+			return b, nil
+		}
+	case []byte:
+		return asBool(string(v))
+	}
+	return false, conversionError(src, "bool")
+}
+
+func asTime(src any) (time.Time, error) {
+	switch v := src.(type) {
+	case time.Time:
+		return v, nil
+	case int64:
+		return time.Unix(v, 0), nil
+	case string:
+		for _, f := range TimestampFormats {
+			if t, err := time.Parse(f, v); err == nil {
+				return t, nil
+			}
+		}
+	case []byte:
+		return asTime(string(v))
+	}
+	return time.Time{}, conversionError(src, "time.Time")
+}
@@ -1,6 +1,6 @@
 module github.com/dopsilva/rdd
 
-go 1.21.0
+go 1.22
 
 require (
 	github.com/google/uuid v1.4.0
@@ -0,0 +1,111 @@
+package rdd
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNotInTransaction é retornado pelas operações que exigem uma transação
+var ErrNotInTransaction = errors.New("rdd: not in a transaction")
+
+// identityMap mantém uma única instância de cada entidade, identificada pela tabela e
+// primary key, durante a transação
+type identityMap struct {
+	mutex    sync.Mutex
+	entities map[string]any
+}
+
+// EnableIdentityMap habilita o identity map na transação. Os Select executados na
+// transação e nos seus savepoints retornam a mesma instância da entidade para o mesmo
+// registro até o Commit ou Rollback. O Seek e o SeekUnique carregam a própria entidade,
+// copiando os valores da instância registrada.
+func EnableIdentityMap(db Database) error {
+	tx := rootTransaction(db)
+	if tx == nil {
+		return ErrNotInTransaction
+	}
+	if tx.identities == nil {
+		tx.identities = &identityMap{entities: make(map[string]any)}
+	}
+	return nil
+}
+
+// rootTransaction retorna a transação principal do banco de dados
+func rootTransaction(db Database) *TransactionWrapper {
+	tx, ok := db.(*TransactionWrapper)
+	if !ok {
+		return nil
+	}
+	for tx.parent != nil {
+		tx = tx.parent
+	}
+	return tx
+}
+
+// identitiesOf retorna o identity map da transação, se habilitado
+func identitiesOf(db Database) *identityMap {
+	if tx := rootTransaction(db); tx != nil {
+		return tx.identities
+	}
+	return nil
+}
+
+func (m *identityMap) load(key string) (any, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	e, ok := m.entities[key]
+	return e, ok
+}
+
+func (m *identityMap) store(key string, e any) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.entities[key] = e
+}
+
+func (m *identityMap) remove(key string, e any) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.entities[key] == e {
+		delete(m.entities, key)
+	}
+}
+
+func (m *identityMap) clear() {
+	if m == nil {
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	clear(m.entities)
+}
+
+// identify retorna a instância do identity map com a mesma primary key da entidade.
+// Se não existir, a entidade é registrada no identity map.
+func identify[T any](db Database, e *T) *T {
+	m := identitiesOf(db)
+	if m == nil {
+		return e
+	}
+
+	w := any(e).(Workarea[T]).instance()
+	key, ok := w.identityKey()
+	if !ok {
+		return e
+	}
+
+	if v, ok := m.load(key); ok && v != any(e) {
+		// a instância é compartilhada, sendo necessário um Close para cada referência
+		any(v).(Workarea[T]).instance().refs++
+		return v.(*T)
+	}
+
+	w.register(m, key)
+
+	return e
+}
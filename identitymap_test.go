@@ -0,0 +1,188 @@
+package rdd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dopsilva/rdd/engine"
+)
+
+func TestIdentityMap(t *testing.T) {
+	queries := 0
+	counter := func(ctx context.Context, call *Call, next Handler) (*CallResult, error) {
+		if call.Kind == QueryCall || call.Kind == QueryRowCall {
+			queries++
+		}
+		return next(ctx, call)
+	}
+
+	db, err := Connect(engine.SQLite, ":memory:", WithInterceptors(counter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.CreateTable(schemaOf[Usuario](), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("insert into usuarios (id, email, nome, incluido_em) values ('u1', 'a@a.com', 'Ana', '2024-01-01')"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EnableIdentityMap(db); !errors.Is(err, ErrNotInTransaction) {
+		t.Fatalf("esperado %v obtido %v", ErrNotInTransaction, err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := EnableIdentityMap(tx); err != nil {
+		t.Fatal(err)
+	}
+
+	r1, err := Select[Usuario](tx, "select id, email, nome from usuarios")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := Select[Usuario](tx, "select id, nome from usuarios where email = 'a@a.com'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r1[0] != r2[0] {
+		t.Fatal("esperado a mesma instância")
+	}
+
+	r1[0].Nome.Set("Ana Maria")
+	r1.Close()
+	if r2[0].Nome.Get() != "Ana Maria" {
+		t.Fatal("esperado a instância compartilhada após o Close")
+	}
+
+	// o Seek utiliza a instância do identity map sem consultar o banco de dados
+	u := Use[Usuario]()
+	defer u.Close()
+	u.ID.Set("u1")
+
+	queries = 0
+	if err := u.Seek(testContext, tx); err != nil {
+		t.Fatal(err)
+	}
+	if queries != 0 || u.Nome.Get() != "Ana Maria" {
+		t.Fatalf("esperado o valor do identity map obtido %s com %d consultas", u.Nome.Get(), queries)
+	}
+
+	// o savepoint compartilha o identity map
+	sp, err := tx.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r3, err := Select[Usuario](sp, "select id from usuarios")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r3[0] != r2[0] {
+		t.Fatal("esperado a mesma instância no savepoint")
+	}
+	r3.Close()
+	if err := sp.Commit(testContext); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Commit(testContext); err != nil {
+		t.Fatal(err)
+	}
+
+	// após o commit o identity map é descartado
+	tx, err = db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	r4, err := Select[Usuario](tx, "select id, nome from usuarios")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r4.Close()
+	if r4[0] == r2[0] {
+		t.Fatal("não esperado a mesma instância após o commit")
+	}
+	r2.Close()
+}
+
+func TestSeek(t *testing.T) {
+	defer truncateTable(testDatabase, "usuarios")
+
+	if _, err := testDatabase.Exec("insert into usuarios (id, email, nome, incluido_em) values ('u1', 'a@a.com', 'Ana', '2024-01-01')"); err != nil {
+		t.Fatal(err)
+	}
+
+	u := Use[Usuario]()
+	defer u.Close()
+
+	u.ID.Set("u1")
+	if err := u.Seek(testContext, testDatabase); err != nil {
+		t.Fatal(err)
+	}
+	if u.Nome.Get() != "Ana" || u.Changed() {
+		t.Fatalf("valores inesperados %s", u.Nome.Get())
+	}
+
+	u.Reset()
+	u.Email.Set("a@a.com")
+	if err := u.SeekUnique(testContext, testDatabase); err != nil {
+		t.Fatal(err)
+	}
+	if u.ID.Get() != "u1" {
+		t.Fatalf("esperado u1 obtido %s", u.ID.Get())
+	}
+
+	u.Reset()
+	u.ID.Set("u2")
+	if err := u.Seek(testContext, testDatabase); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("esperado %v obtido %v", ErrNotFound, err)
+	}
+}
+
+func TestSeekContext(t *testing.T) {
+	type ctxKey struct{}
+
+	var received any
+	interceptor := func(ctx context.Context, call *Call, next Handler) (*CallResult, error) {
+		received = ctx.Value(ctxKey{})
+		return next(ctx, call)
+	}
+
+	db, err := Connect(engine.SQLite, ":memory:", WithInterceptors(interceptor))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.CreateTable(schemaOf[Usuario](), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("insert into usuarios (id, email, nome, incluido_em) values ('u1', 'a@a.com', 'Ana', '2024-01-01')"); err != nil {
+		t.Fatal(err)
+	}
+
+	u := Use[Usuario]()
+	defer u.Close()
+	u.ID.Set("u1")
+
+	// o contexto do Seek é recebido pelos interceptors
+	if err := u.Seek(context.WithValue(testContext, ctxKey{}, "seek"), db); err != nil {
+		t.Fatal(err)
+	}
+	if received != "seek" {
+		t.Fatalf("esperado o contexto do Seek obtido %v", received)
+	}
+
+	ctx, cancel := context.WithCancel(testContext)
+	cancel()
+	if err := u.Seek(ctx, db); !errors.Is(err, context.Canceled) {
+		t.Fatalf("esperado %v obtido %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,155 @@
+package rdd
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/dopsilva/rdd/schema"
+)
+
+// CallKind identifica a operação executada no banco de dados
+type CallKind int
+
+const (
+	ExecCall CallKind = iota + 1
+	QueryCall
+	QueryRowCall
+	BeginCall
+	CommitCall
+	RollbackCall
+)
+
+func (k CallKind) String() string {
+	switch k {
+	case ExecCall:
+		return "exec"
+	case QueryCall:
+		return "query"
+	case QueryRowCall:
+		return "query_row"
+	case BeginCall:
+		return "begin"
+	case CommitCall:
+		return "commit"
+	case RollbackCall:
+		return "rollback"
+	}
+	return "unknown"
+}
+
+// Call descreve a chamada recebida pelos interceptors. Query e Args podem ser
+// alterados antes de chamar o próximo handler. Nos savepoints o Query contém o
+// comando executado (savepoint, release savepoint ou rollback to).
+type Call struct {
+	Kind  CallKind
+	Query string
+	Args  []any
+
+	// Entity e Schema são informados quando a chamada parte de uma workarea
+	Entity string
+	Schema *schema.Table
+
+	// Database é o wrapper que recebeu a chamada
+	Database Database
+}
+
+// CallResult é o retorno da chamada de acordo com o seu tipo. Se o interceptor interromper
+// a chamada sem o retorno, o Exec não afeta registros e o Query e QueryRow retornam
+// sql.ErrNoRows.
+type CallResult struct {
+	Result sql.Result // ExecCall
+	Rows   *sql.Rows  // QueryCall
+	Row    *Row       // QueryRowCall
+	Tx     Database   // BeginCall
+}
+
+// Handler executa a chamada
+type Handler func(ctx context.Context, call *Call) (*CallResult, error)
+
+// Interceptor envolve a execução das chamadas ao banco de dados. Para continuar
+// a execução o interceptor chama o next; para interromper basta retornar sem chamá-lo.
+type Interceptor func(ctx context.Context, call *Call, next Handler) (*CallResult, error)
+
+// Option configura a conexão no Connect
+type Option func(db *DatabaseWrapper)
+
+// WithInterceptors registra os interceptors na ordem em que devem ser executados
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(db *DatabaseWrapper) {
+		db.interceptors = append(db.interceptors, interceptors...)
+	}
+}
+
+// invoker é implementado pelos wrappers que executam a cadeia de interceptors
+type invoker interface {
+	invoke(ctx context.Context, call *Call) (*CallResult, error)
+}
+
+// intercept executa a cadeia de interceptors terminando no handler final
+func intercept(ctx context.Context, interceptors []Interceptor, call *Call, final Handler) (*CallResult, error) {
+	h := final
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		ic, next := interceptors[i], h
+		h = func(ctx context.Context, call *Call) (*CallResult, error) {
+			return ic(ctx, call, next)
+		}
+	}
+
+	res, err := h(ctx, call)
+	if res == nil {
+		res = &CallResult{}
+	}
+
+	return res, translateError(err)
+}
+
+// execContext executa o comando através dos interceptors quando disponível
+func execContext(ctx context.Context, db Database, call *Call) (sql.Result, error) {
+	call.Kind = ExecCall
+	if i, ok := db.(invoker); ok {
+		res, err := i.invoke(ctx, call)
+		if err == nil && res.Result == nil {
+			return driver.RowsAffected(0), nil
+		}
+		return res.Result, err
+	}
+	return db.Exec(call.Query, call.Args...)
+}
+
+// queryContext executa a query através dos interceptors quando disponível
+func queryContext(ctx context.Context, db Database, call *Call) (*sql.Rows, error) {
+	call.Kind = QueryCall
+	if i, ok := db.(invoker); ok {
+		res, err := i.invoke(ctx, call)
+		if err == nil && res.Rows == nil {
+			return nil, sql.ErrNoRows
+		}
+		return res.Rows, err
+	}
+	return db.Query(call.Query, call.Args...)
+}
+
+// queryRowContext executa a query através dos interceptors quando disponível
+func queryRowContext(ctx context.Context, db Database, call *Call) *Row {
+	call.Kind = QueryRowCall
+	if i, ok := db.(invoker); ok {
+		return rowResult(i.invoke(ctx, call))
+	}
+	return db.QueryRow(call.Query, call.Args...)
+}
+
+func rowResult(res *CallResult, err error) *Row {
+	if err != nil {
+		return &Row{err: err}
+	}
+	if res.Row == nil {
+		return &Row{err: sql.ErrNoRows}
+	}
+	return res.Row
+}
+
+// newCall cria a chamada ao banco de dados com as informações da entidade T
+func newCall[T any](q string, args []any) *Call {
+	return &Call{Query: q, Args: args, Entity: entityName[T](), Schema: registeredSchemas[entityType[T]()]}
+}
@@ -0,0 +1,108 @@
+package rdd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dopsilva/rdd/engine"
+	"github.com/dopsilva/rdd/schema"
+)
+
+func TestInterceptors(t *testing.T) {
+	calls := make([]Call, 0)
+	fault := errors.New("falha injetada")
+
+	recorder := func(ctx context.Context, call *Call, next Handler) (*CallResult, error) {
+		calls = append(calls, *call)
+		return next(ctx, call)
+	}
+
+	injector := func(ctx context.Context, call *Call, next Handler) (*CallResult, error) {
+		if strings.HasPrefix(call.Query, "delete") {
+			return nil, fault
+		}
+		// interrompe sem o retorno
+		if strings.HasPrefix(call.Query, "update") {
+			return nil, nil
+		}
+		// reescreve a query
+		call.Query = strings.ReplaceAll(call.Query, "$TABLE", "usuarios")
+		return next(ctx, call)
+	}
+
+	db, err := Connect(engine.SQLite, ":memory:", WithInterceptors(recorder, injector))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.CreateTable(schemaOf[Usuario](), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u := Use[Usuario]()
+	defer u.Close()
+
+	u.Email.Set("dopslv@gmail.com")
+	u.Nome.Set("Daniel")
+	u.IncluidoEm.Set(time.Now())
+
+	if err := u.Append(testContext, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Commit(testContext); err != nil {
+		t.Fatal(err)
+	}
+
+	var n int
+	if err := db.QueryRow("select count(*) from $TABLE").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("esperado 1 obtido %d", n)
+	}
+
+	if _, err := db.Exec("delete from usuarios"); !errors.Is(err, fault) {
+		t.Fatalf("esperado %v obtido %v", fault, err)
+	}
+
+	r, err := db.Exec("update usuarios set nome = 'Ana'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := r.RowsAffected(); err != nil || n != 0 {
+		t.Fatalf("esperado nenhum registro afetado obtido %d (%v)", n, err)
+	}
+
+	expected := []struct {
+		kind   CallKind
+		entity string
+		schema *schema.Table
+	}{
+		{ExecCall, "", nil},
+		{BeginCall, "", nil},
+		{QueryRowCall, "Usuario", schemaOf[Usuario]()},
+		{CommitCall, "", nil},
+		{QueryRowCall, "", nil},
+		{ExecCall, "", nil},
+		{ExecCall, "", nil},
+	}
+
+	if len(calls) != len(expected) {
+		t.Fatalf("esperado %d chamadas obtido %d", len(expected), len(calls))
+	}
+	for i, e := range expected {
+		if calls[i].Kind != e.kind || calls[i].Entity != e.entity || calls[i].Schema != e.schema {
+			t.Fatalf("chamada %d: esperado %s %q obtido %s %q", i, e.kind, e.entity, calls[i].Kind, calls[i].Entity)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package rdd
+
+import "github.com/dopsilva/rdd/builder"
+
+// LockOption define o bloqueio pessimista dos registros lidos pelo Seek e SeekUnique.
+// O bloqueio é mantido até o Commit ou Rollback, portanto exige uma transação.
+// No SQLite o bloqueio é ignorado, pois a transação bloqueia o banco de dados na primeira gravação.
+type LockOption func(l *builder.Lock)
+
+// ForUpdate bloqueia os registros para alteração (select ... for update)
+func ForUpdate() LockOption {
+	return func(l *builder.Lock) {
+		l.Mode = builder.LockForUpdate
+	}
+}
+
+// ForShare bloqueia os registros contra alteração, permitindo outras leituras (select ... for share)
+func ForShare() LockOption {
+	return func(l *builder.Lock) {
+		l.Mode = builder.LockForShare
+	}
+}
+
+// NoWait retorna erro se o registro já estiver bloqueado, ao invés de aguardar
+func NoWait() LockOption {
+	return func(l *builder.Lock) {
+		l.NoWait = true
+	}
+}
+
+// SkipLocked ignora os registros já bloqueados. No Seek, o registro bloqueado retorna ErrNotFound.
+func SkipLocked() LockOption {
+	return func(l *builder.Lock) {
+		l.SkipLocked = true
+	}
+}
+
+// lockOf retorna o bloqueio das opções, validando se o banco de dados está em uma transação
+func lockOf(db Database, options []LockOption) (builder.Lock, error) {
+	l := builder.Lock{}
+	if len(options) == 0 {
+		return l, nil
+	}
+
+	for _, o := range options {
+		o(&l)
+	}
+
+	if !db.WithinTransaction() {
+		return l, ErrNotInTransaction
+	}
+
+	return l, l.Validate()
+}
@@ -0,0 +1,107 @@
+package rdd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dopsilva/rdd/builder"
+	"github.com/dopsilva/rdd/engine"
+)
+
+func TestSeekLock(t *testing.T) {
+	db, err := Connect(engine.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.CreateTable(schemaOf[Moeda](), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("insert into moedas (codigo, simbolo, nome) values ('EUR', '€', 'Euro')"); err != nil {
+		t.Fatal(err)
+	}
+
+	m := Use[Moeda]()
+	defer m.Close()
+	m.Codigo.Set("EUR")
+
+	// o bloqueio exige uma transação
+	if err := m.Seek(testContext, db, ForUpdate()); !errors.Is(err, ErrNotInTransaction) {
+		t.Fatalf("esperado %v obtido %v", ErrNotInTransaction, err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if err := m.Seek(testContext, tx, ForUpdate(), NoWait()); err != nil {
+		t.Fatal(err)
+	}
+	if m.Nome.Get() != "Euro" {
+		t.Fatalf("esperado Euro obtido %s", m.Nome.Get())
+	}
+
+	m.Reset()
+	m.Simbolo.Set("€")
+	if err := m.SeekUnique(testContext, tx, ForShare(), SkipLocked()); err != nil {
+		t.Fatal(err)
+	}
+	if m.Codigo.Get() != "EUR" {
+		t.Fatalf("esperado EUR obtido %s", m.Codigo.Get())
+	}
+
+	if err := m.Seek(testContext, tx, ForUpdate(), NoWait(), SkipLocked()); err == nil {
+		t.Fatal("esperado erro com nowait e skip locked")
+	}
+	if err := m.Seek(testContext, tx, SkipLocked()); err == nil {
+		t.Fatal("esperado erro com skip locked sem bloqueio")
+	}
+}
+
+func TestSelectLock(t *testing.T) {
+	table := schemaOf[Moeda]()
+
+	q, _, err := builder.New(engine.SQLite).Select(*table, &builder.SelectOptions{Lock: builder.Lock{Mode: builder.LockForUpdate, SkipLocked: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != `select "codigo", "nome", "simbolo" from "moedas"` {
+		t.Fatalf("select inesperado %s", q)
+	}
+
+	if _, _, err := builder.New(engine.SQLite).Select(*table, &builder.SelectOptions{Lock: builder.Lock{NoWait: true}}); err == nil {
+		t.Fatal("esperado erro com nowait sem bloqueio")
+	}
+}
+
+func TestLockClause(t *testing.T) {
+	for _, c := range []struct {
+		lock   builder.Lock
+		engine engine.Engine
+		clause string
+	}{
+		{builder.Lock{}, engine.Cockroach, ""},
+		{builder.Lock{Mode: builder.LockForUpdate}, engine.SQLite, ""},
+		{builder.Lock{Mode: builder.LockForUpdate}, engine.Cockroach, " for update"},
+		{builder.Lock{Mode: builder.LockForShare, NoWait: true}, engine.Cockroach, " for share nowait"},
+		{builder.Lock{Mode: builder.LockForUpdate, SkipLocked: true}, engine.Cockroach, " for update skip locked"},
+	} {
+		clause, err := c.lock.Clause(c.engine)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if clause != c.clause {
+			t.Fatalf("esperado %q obtido %q", c.clause, clause)
+		}
+	}
+
+	if _, err := (builder.Lock{Mode: builder.LockForUpdate}).Clause(engine.SQLServer); err == nil {
+		t.Fatal("esperado erro com engine sem suporte")
+	}
+	if _, err := (builder.Lock{Mode: builder.LockForUpdate, NoWait: true, SkipLocked: true}).Clause(engine.Cockroach); err == nil {
+		t.Fatal("esperado erro com nowait e skip locked")
+	}
+}
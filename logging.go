@@ -0,0 +1,132 @@
+package rdd
+
+import (
+	"context"
+	"database/sql/driver"
+	"log/slog"
+	"time"
+
+	"github.com/dopsilva/rdd/field"
+)
+
+const redacted = "[REDACTED]"
+
+// WithLogger registra o log estruturado de todas as chamadas ao banco de dados.
+// As chamadas são registradas no nível debug, as lentas no nível warn e as que
+// falharam no nível error.
+func WithLogger(logger *slog.Logger) Option {
+	return func(db *DatabaseWrapper) {
+		db.logger = logger
+	}
+}
+
+// WithSlowQueryThreshold define a duração a partir da qual a chamada é considerada lenta
+func WithSlowQueryThreshold(d time.Duration) Option {
+	return func(db *DatabaseWrapper) {
+		db.slowThreshold = d
+	}
+}
+
+// logInterceptor cria o interceptor que registra as chamadas no logger
+func logInterceptor(logger *slog.Logger, slowThreshold time.Duration) Interceptor {
+	return func(ctx context.Context, call *Call, next Handler) (*CallResult, error) {
+		start := time.Now()
+		res, err := next(ctx, call)
+		duration := time.Since(start)
+
+		level := slog.LevelDebug
+		msg := "rdd: " + call.Kind.String()
+		if err != nil {
+			level = slog.LevelError
+		} else if slowThreshold > 0 && duration >= slowThreshold {
+			level = slog.LevelWarn
+			msg = "rdd: slow " + call.Kind.String()
+		}
+
+		if !logger.Enabled(ctx, level) {
+			return res, err
+		}
+
+		attrs := make([]slog.Attr, 0, 8)
+		if call.Query != "" {
+			attrs = append(attrs, slog.String("statement", call.Query))
+		}
+		if len(call.Args) > 0 {
+			attrs = append(attrs, slog.Any("args", logArgs(call.Args)))
+		}
+		attrs = append(attrs, slog.Duration("duration", duration))
+		if res != nil && res.Result != nil {
+			if n, err := res.Result.RowsAffected(); err == nil {
+				attrs = append(attrs, slog.Int64("rows_affected", n))
+			}
+		}
+		if call.Entity != "" {
+			attrs = append(attrs, slog.String("entity", call.Entity))
+		}
+		if tx, ok := call.Database.(*TransactionWrapper); ok {
+			attrs = append(attrs, slog.String("tx", tx.id))
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+
+		logger.LogAttrs(ctx, level, msg, attrs...)
+
+		return res, err
+	}
+}
+
+// Sensitive marca o argumento da query como sensível, ocultando o seu valor no log.
+// Os argumentos das colunas rdd-sensitive criados pelas workareas já são marcados.
+func Sensitive(v any) driver.Valuer {
+	return sensitiveArg{value: v}
+}
+
+type sensitiveArg struct {
+	value any
+}
+
+func (a sensitiveArg) Value() (driver.Value, error) {
+	return driver.DefaultParameterConverter.ConvertValue(a.value)
+}
+
+// sensitiveValue marca o valor do campo como sensível quando a coluna é rdd-sensitive
+func sensitiveValue(fi field.FieldInstance, v any) any {
+	if fi.Schema.Sensitive {
+		return Sensitive(v)
+	}
+	return v
+}
+
+// logArgs obtém os valores dos argumentos, ocultando as colunas sensíveis
+func logArgs(args []any) []any {
+	values := make([]any, len(args))
+
+	for i, arg := range args {
+		if b, ok := arg.(field.Bindable); ok && b.Schema().Sensitive {
+			values[i] = redacted
+			continue
+		}
+		if _, ok := arg.(sensitiveArg); ok {
+			values[i] = redacted
+			continue
+		}
+
+		value := arg
+		if v, ok := arg.(driver.Valuer); ok {
+			if dv, err := v.Value(); err == nil {
+				value = dv
+			}
+		}
+		// tipos sql.Null* retornam a própria estrutura
+		if v, ok := value.(driver.Valuer); ok {
+			if dv, err := v.Value(); err == nil {
+				value = dv
+			}
+		}
+
+		values[i] = value
+	}
+
+	return values
+}
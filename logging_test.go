@@ -0,0 +1,152 @@
+package rdd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dopsilva/rdd/engine"
+	"github.com/dopsilva/rdd/field"
+)
+
+type Credencial struct {
+	Workarea[Credencial] `rdd-table:"credenciais"`
+
+	Login field.Field[string] `rdd-column:"login" rdd-primary-key:"true"`
+	Senha field.Field[string] `rdd-column:"senha" rdd-sensitive:"true"`
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	db, err := Connect(engine.SQLite, ":memory:", WithLogger(logger), WithSlowQueryThreshold(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	c := Use[Credencial]()
+	defer c.Close()
+
+	if err := db.CreateTable(c.Schema(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Login.Set("dopslv")
+	c.Senha.Set("segredo")
+
+	if err := c.Append(testContext, tx); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(testContext); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buf.String(), "segredo") {
+		t.Fatal("o valor da coluna sensível não deveria ser registrado")
+	}
+
+	var insert map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatal(err)
+		}
+		if entry["level"] != "DEBUG" {
+			t.Fatalf("esperado nível DEBUG obtido %v", entry["level"])
+		}
+		if s, ok := entry["statement"].(string); ok && strings.HasPrefix(s, "insert") {
+			insert = entry
+		}
+	}
+
+	if insert == nil {
+		t.Fatal("esperado o registro do insert")
+	}
+	if insert["entity"] != "Credencial" || insert["rows_affected"] != float64(1) || insert["tx"] == "" {
+		t.Fatalf("registro incompleto %v", insert)
+	}
+	if !strings.Contains(fmt.Sprint(insert["args"]), redacted) {
+		t.Fatalf("esperado argumento oculto obtido %v", insert["args"])
+	}
+}
+
+func TestSlowQueryLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	db, err := Connect(engine.SQLite, ":memory:", WithLogger(logger), WithSlowQueryThreshold(time.Nanosecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("select 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["level"] != "WARN" || entry["msg"] != "rdd: slow exec" {
+		t.Fatalf("esperado registro de query lenta obtido %v", entry)
+	}
+}
+
+type Contribuinte struct {
+	Workarea[Contribuinte] `rdd-table:"contribuintes"`
+
+	ID  field.Field[int64]  `rdd-column:"id" rdd-primary-key:"true"`
+	CPF field.Field[string] `rdd-column:"cpf" rdd-unique-key:"true" rdd-sensitive:"true"`
+}
+
+func TestLoggerSensitiveArgs(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	db, err := Connect(engine.SQLite, ":memory:", WithLogger(logger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	c := Use[Contribuinte]()
+	defer c.Close()
+
+	if err := db.CreateTable(c.Schema(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("insert into contribuintes (id, cpf) values ($1, $2)", 1, Sensitive("12345678900")); err != nil {
+		t.Fatal(err)
+	}
+
+	// a coluna sensível usada como chave da busca
+	c.CPF.Set("12345678900")
+	if err := c.SeekUnique(testContext, db); err != nil {
+		t.Fatal(err)
+	}
+	if c.ID.Get() != 1 {
+		t.Fatalf("esperado 1 obtido %d", c.ID.Get())
+	}
+
+	if strings.Contains(buf.String(), "12345678900") {
+		t.Fatalf("o valor da coluna sensível não deveria ser registrado: %s", buf.String())
+	}
+	if strings.Count(buf.String(), redacted) != 2 {
+		t.Fatalf("esperado dois argumentos ocultos: %s", buf.String())
+	}
+}
@@ -0,0 +1,273 @@
+package rdd
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// Resultado das operações informado ao Observer
+const (
+	OutcomeSuccess  = "success"
+	OutcomeError    = "error"
+	OutcomeCommit   = "commit"
+	OutcomeRollback = "rollback"
+)
+
+// OperationMetric é a medição de uma operação da workarea
+type OperationMetric struct {
+	Entity    string
+	Operation string // append, replace, remove, soft-remove, select, ...
+	Engine    string
+	Outcome   string // success ou error
+	Duration  time.Duration
+}
+
+// TransactionMetric é a medição de uma transação, do Begin até o Commit ou Rollback
+type TransactionMetric struct {
+	Engine   string
+	Outcome  string // commit, rollback ou error
+	Duration time.Duration
+}
+
+// Observer recebe as métricas das operações no banco de dados. As implementações
+// devem ser seguras para uso concorrente e não devem bloquear.
+type Observer interface {
+	ObserveOperation(m OperationMetric)
+	ObserveTransaction(m TransactionMetric)
+	ObservePool(engine string, stats sql.DBStats)
+}
+
+// Tracer é implementada opcionalmente pelo Observer para o tracing das operações da
+// workarea e das consultas (append, select, ...). StartOperation é executado no início da
+// operação e retorna o contexto com o span, repassado às chamadas ao banco de dados e aos
+// interceptors, e a função que encerra o span com o erro da operação.
+type Tracer interface {
+	StartOperation(ctx context.Context, entity, operation string) (context.Context, func(err error))
+}
+
+// WithObserver registra o observer das métricas
+func WithObserver(o Observer) Option {
+	return func(db *DatabaseWrapper) {
+		db.observer = o
+	}
+}
+
+// WithPoolStatsInterval define o intervalo em que as estatísticas do pool são enviadas ao observer
+func WithPoolStatsInterval(d time.Duration) Option {
+	return func(db *DatabaseWrapper) {
+		db.statsInterval = d
+	}
+}
+
+// observable é implementada pelos wrappers que possuem observer
+type observable interface {
+	metrics() (Observer, string)
+}
+
+// startOperation inicia o span da operação, se o observer do banco de dados implementar o
+// Tracer. A função retornada encerra o span e informa a medição da operação ao observer.
+func startOperation(ctx context.Context, db Database, entity, operation string) (context.Context, func(err *error)) {
+	o, ok := db.(observable)
+	if !ok {
+		return ctx, func(*error) {}
+	}
+
+	obs, engine := o.metrics()
+	if obs == nil {
+		return ctx, func(*error) {}
+	}
+
+	end := func(error) {}
+	if t, ok := obs.(Tracer); ok {
+		ctx, end = t.StartOperation(ctx, entity, operation)
+	}
+
+	start := time.Now()
+	return ctx, func(err *error) {
+		end(*err)
+
+		outcome := OutcomeSuccess
+		if *err != nil {
+			outcome = OutcomeError
+		}
+
+		obs.ObserveOperation(OperationMetric{
+			Entity:    entity,
+			Operation: operation,
+			Engine:    engine,
+			Outcome:   outcome,
+			Duration:  time.Since(start),
+		})
+	}
+}
+
+// reportPoolStats envia periodicamente as estatísticas do pool ao observer
+func (db *DatabaseWrapper) reportPoolStats() {
+	t := time.NewTicker(db.statsInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			db.observer.ObservePool(db.engine.String(), db.db.Stats())
+		case <-db.done:
+			return
+		}
+	}
+}
+
+// DefaultBuckets são os limites dos histogramas de latência do MemoryObserver
+var DefaultBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// Histogram acumula as durações observadas. Buckets[i] contém a quantidade de
+// medições menores ou iguais a DefaultBuckets[i]; a última posição contém as demais.
+type Histogram struct {
+	Count   int64
+	Sum     time.Duration
+	Buckets []int64
+}
+
+func (h *Histogram) observe(d time.Duration) {
+	if h.Buckets == nil {
+		h.Buckets = make([]int64, len(DefaultBuckets)+1)
+	}
+
+	h.Count++
+	h.Sum += d
+
+	for i, b := range DefaultBuckets {
+		if d <= b {
+			h.Buckets[i]++
+			return
+		}
+	}
+	h.Buckets[len(DefaultBuckets)]++
+}
+
+// OperationKey identifica as séries de métricas das operações
+type OperationKey struct {
+	Entity    string
+	Operation string
+	Engine    string
+	Outcome   string
+}
+
+// TransactionKey identifica as séries de métricas das transações
+type TransactionKey struct {
+	Engine  string
+	Outcome string
+}
+
+// MemoryObserver é a implementação de referência do Observer que mantém as métricas em memória
+type MemoryObserver struct {
+	mu           sync.Mutex
+	operations   map[OperationKey]*Histogram
+	transactions map[TransactionKey]*Histogram
+	pool         sql.DBStats
+}
+
+func NewMemoryObserver() *MemoryObserver {
+	return &MemoryObserver{
+		operations:   make(map[OperationKey]*Histogram),
+		transactions: make(map[TransactionKey]*Histogram),
+	}
+}
+
+func (m *MemoryObserver) ObserveOperation(om OperationMetric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k := OperationKey{Entity: om.Entity, Operation: om.Operation, Engine: om.Engine, Outcome: om.Outcome}
+	h, ok := m.operations[k]
+	if !ok {
+		h = &Histogram{}
+		m.operations[k] = h
+	}
+	h.observe(om.Duration)
+}
+
+func (m *MemoryObserver) ObserveTransaction(tm TransactionMetric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k := TransactionKey{Engine: tm.Engine, Outcome: tm.Outcome}
+	h, ok := m.transactions[k]
+	if !ok {
+		h = &Histogram{}
+		m.transactions[k] = h
+	}
+	h.observe(tm.Duration)
+}
+
+func (m *MemoryObserver) ObservePool(engine string, stats sql.DBStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pool = stats
+}
+
+// Operation retorna uma cópia do histograma da série informada
+func (m *MemoryObserver) Operation(k OperationKey) Histogram {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return copyHistogram(m.operations[k])
+}
+
+// Operations retorna as séries de operações observadas
+func (m *MemoryObserver) Operations() []OperationKey {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]OperationKey, 0, len(m.operations))
+	for k := range m.operations {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Transaction retorna uma cópia do histograma da série de transações informada
+func (m *MemoryObserver) Transaction(k TransactionKey) Histogram {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return copyHistogram(m.transactions[k])
+}
+
+// Transactions retorna as séries de transações observadas
+func (m *MemoryObserver) Transactions() []TransactionKey {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]TransactionKey, 0, len(m.transactions))
+	for k := range m.transactions {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Pool retorna as últimas estatísticas do pool recebidas
+func (m *MemoryObserver) Pool() sql.DBStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.pool
+}
+
+func copyHistogram(h *Histogram) Histogram {
+	if h == nil {
+		return Histogram{}
+	}
+	return Histogram{Count: h.Count, Sum: h.Sum, Buckets: append([]int64(nil), h.Buckets...)}
+}
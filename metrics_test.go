@@ -0,0 +1,185 @@
+package rdd
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dopsilva/rdd/engine"
+)
+
+func TestObserver(t *testing.T) {
+	obs := NewMemoryObserver()
+
+	db, err := Connect(engine.SQLite, ":memory:", WithObserver(obs), WithPoolStatsInterval(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.CreateTable(schemaOf[Usuario](), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	u := Use[Usuario]()
+	defer u.Close()
+
+	u.Email.Set("dopslv@gmail.com")
+	u.Nome.Set("Daniel")
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Append(testContext, tx); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(testContext); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err = db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Select[Usuario](db, "select nome from usuarios")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Close()
+
+	// violação da unique key
+	d := Use[Usuario]()
+	defer d.Close()
+
+	d.Email.Set("dopslv@gmail.com")
+	d.Nome.Set("Outro")
+	if err := d.Append(testContext, db); !errors.Is(err, UniqueViolation) {
+		t.Fatalf("esperado %v obtido %v", UniqueViolation, err)
+	}
+
+	// falha na validação
+	c := Use[Cliente]()
+	defer c.Close()
+	if err := c.Append(testContext, db); err == nil {
+		t.Fatal("esperado erro de validação")
+	}
+
+	var tests = []struct {
+		key   OperationKey
+		count int64
+	}{
+		{OperationKey{Entity: "Usuario", Operation: "append", Engine: "sqlite", Outcome: OutcomeSuccess}, 1},
+		{OperationKey{Entity: "Usuario", Operation: "append", Engine: "sqlite", Outcome: OutcomeError}, 1},
+		{OperationKey{Entity: "Usuario", Operation: "select", Engine: "sqlite", Outcome: OutcomeSuccess}, 1},
+		{OperationKey{Entity: "Cliente", Operation: "append", Engine: "sqlite", Outcome: OutcomeError}, 1},
+	}
+
+	for _, test := range tests {
+		if h := obs.Operation(test.key); h.Count != test.count {
+			t.Fatalf("%v: esperado %d obtido %d", test.key, test.count, h.Count)
+		}
+	}
+
+	if n := obs.Transaction(TransactionKey{Engine: "sqlite", Outcome: OutcomeCommit}).Count; n != 1 {
+		t.Fatalf("esperado 1 commit obtido %d", n)
+	}
+	if n := obs.Transaction(TransactionKey{Engine: "sqlite", Outcome: OutcomeRollback}).Count; n != 1 {
+		t.Fatalf("esperado 1 rollback obtido %d", n)
+	}
+	if keys := obs.Transactions(); len(keys) != 2 {
+		t.Fatalf("esperado 2 séries de transações obtido %v", keys)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for obs.Pool().OpenConnections == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("esperado estatísticas do pool")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// o Close pode ser executado mais de uma vez
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type spanKey struct{}
+
+// tracingObserver registra os spans das operações
+type tracingObserver struct {
+	*MemoryObserver
+
+	spans []string
+}
+
+func (o *tracingObserver) StartOperation(ctx context.Context, entity, operation string) (context.Context, func(err error)) {
+	return context.WithValue(ctx, spanKey{}, entity+" "+operation), func(err error) {
+		span := entity + " " + operation
+		if err != nil {
+			span += " error"
+		}
+		o.spans = append(o.spans, span)
+	}
+}
+
+func TestTracer(t *testing.T) {
+	obs := &tracingObserver{MemoryObserver: NewMemoryObserver()}
+
+	// os interceptors recebem o contexto com o span
+	calls := make([]string, 0)
+	recorder := func(ctx context.Context, call *Call, next Handler) (*CallResult, error) {
+		if span, ok := ctx.Value(spanKey{}).(string); ok {
+			calls = append(calls, span)
+		}
+		return next(ctx, call)
+	}
+
+	db, err := Connect(engine.SQLite, ":memory:", WithObserver(obs), WithInterceptors(recorder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.CreateTable(schemaOf[Usuario](), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	u := Use[Usuario]()
+	defer u.Close()
+
+	u.Email.Set("dopslv@gmail.com")
+	u.Nome.Set("Daniel")
+	if err := u.Append(testContext, db); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Count[Usuario](db, Where("nome", "like", "D%")); err == nil {
+		t.Fatal("esperado erro com operador inválido")
+	}
+	res, err := Select[Usuario](db, "select nome from usuarios")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Close()
+
+	expected := []string{"Usuario append", "Usuario aggregate error", "Usuario select"}
+	if !slices.Equal(obs.spans, expected) {
+		t.Fatalf("esperado %v obtido %v", expected, obs.spans)
+	}
+	if !slices.Contains(calls, "Usuario append") || !slices.Contains(calls, "Usuario select") {
+		t.Fatalf("esperado o span nas chamadas obtido %v", calls)
+	}
+	if h := obs.Operation(OperationKey{Entity: "Usuario", Operation: "append", Engine: "sqlite", Outcome: OutcomeSuccess}); h.Count != 1 {
+		t.Fatalf("esperado a métrica do append obtido %d", h.Count)
+	}
+}
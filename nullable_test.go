@@ -0,0 +1,91 @@
+package rdd
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dopsilva/rdd/field"
+)
+
+type Contato struct {
+	Workarea[Contato] `rdd-table:"contatos"`
+
+	ID         field.Field[string]           `rdd-column:"id" rdd-primary-key:"true" rdd-auto-generated:"true" rdd-default:"new_uuid"`
+	Nome       field.Field[string]           `rdd-column:"nome"`
+	Apelido    field.Nullable[string]        `rdd-column:"apelido"`
+	Nascimento field.Nullable[time.Time]     `rdd-column:"nascimento"`
+	Filhos     field.Nullable[int64]         `rdd-column:"filhos" rdd-nullable:"false"`
+	Limite     field.Nullable[field.Decimal] `rdd-column:"limite"`
+}
+
+func init() {
+	Register[Contato]()
+}
+
+func TestNullable(t *testing.T) {
+	defer truncateTable(testDatabase, "contatos")
+
+	c := Use[Contato]()
+	defer c.Close()
+
+	s := c.Schema()
+	if !s.Fields["apelido"].Nullable || !s.Fields["nascimento"].Nullable || s.Fields["filhos"].Nullable {
+		t.Fatal("nullable inferido incorretamente")
+	}
+
+	apelido := "Dani"
+	nascimento := time.Date(1980, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	data := struct {
+		Nome       string              `rdd-column:"nome"`
+		Apelido    *string             `rdd-column:"apelido"`
+		Nascimento sql.Null[time.Time] `rdd-column:"nascimento"`
+		Filhos     int64               `rdd-column:"filhos"`
+		Limite     *field.Decimal      `rdd-column:"limite"`
+	}{Nome: "Daniel", Apelido: &apelido, Nascimento: sql.Null[time.Time]{V: nascimento, Valid: true}, Filhos: 2}
+
+	if err := c.Load(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := c.Apelido.Get(); !ok || v != "Dani" {
+		t.Fatalf("esperado Dani obtido %q (%t)", v, ok)
+	}
+	if !c.Limite.IsNull() {
+		t.Fatal("esperado limite nulo")
+	}
+
+	if err := c.Append(testContext, testDatabase); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Apelido.SetNull()
+	if !c.Changed() {
+		t.Fatal("esperado alteração")
+	}
+	if err := c.Replace(testContext, testDatabase); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Select[Contato](testDatabase, "select id, nome, apelido, nascimento, filhos, limite from contatos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Close()
+
+	if res.Len() != 1 {
+		t.Fatalf("esperado 1 obtido %d", res.Len())
+	}
+
+	r := res[0]
+	if !r.Apelido.IsNull() || !r.Limite.IsNull() {
+		t.Fatal("esperado apelido e limite nulos")
+	}
+	if v, ok := r.Nascimento.Get(); !ok || !v.Equal(nascimento) {
+		t.Fatalf("esperado %v obtido %v", nascimento, v)
+	}
+	if v, ok := r.Filhos.Get(); !ok || v != 2 {
+		t.Fatalf("esperado 2 obtido %d", v)
+	}
+}
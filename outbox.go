@@ -0,0 +1,212 @@
+package rdd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dopsilva/rdd/field"
+)
+
+// OutboxEvent é o evento de domínio gravado na tabela de outbox
+type OutboxEvent struct {
+	Workarea[OutboxEvent] `rdd-table:"rdd_outbox"`
+
+	ID          field.Field[string]       `rdd-column:"id" rdd-primary-key:"true" rdd-auto-generated:"true" rdd-default:"new_uuid"`
+	EntityName  field.Field[string]       `rdd-column:"entity" rdd-default:"''"`
+	EntityKey   field.Field[string]       `rdd-column:"entity_key" rdd-default:"''"`
+	Type        field.Field[string]       `rdd-column:"event_type"`
+	Payload     field.Field[field.JSON]   `rdd-column:"payload"`
+	CreatedAt   field.Field[time.Time]    `rdd-column:"created_at"`
+	AvailableAt field.Field[time.Time]    `rdd-column:"available_at"`
+	Attempts    field.Field[int64]        `rdd-column:"attempts" rdd-default:"0"`
+	DeliveredAt field.Nullable[time.Time] `rdd-column:"delivered_at"`
+	LastError   field.Field[string]       `rdd-column:"last_error" rdd-default:"''"`
+}
+
+// Event é o evento de domínio a ser gravado no outbox
+type Event struct {
+	Type   string
+	Entity string
+	Key    string
+	// Payload é gravado como json
+	Payload any
+}
+
+// EventOf cria o evento da entidade, identificada pelo nome e primary key
+func EventOf(e Persistable, eventType string, payload any) Event {
+	ev := Event{Type: eventType, Entity: e.Entity(), Payload: payload}
+	if pk, ok := e.Schema().PrimaryKey(); ok {
+		if addr := e.GetFieldsAddr([]string{pk}); len(addr) == 1 {
+			if v, err := fieldValue(field.FieldInstance{Addr: addr[0]}); err == nil && v != nil {
+				ev.Key = fmt.Sprint(relationKey(v))
+			}
+		}
+	}
+	return ev
+}
+
+// CreateOutboxTable cria a tabela de outbox
+func CreateOutboxTable(db Database) error {
+	return db.CreateTable(schemaOf[OutboxEvent](), nil)
+}
+
+// Enqueue grava os eventos na tabela de outbox. Dentro de uma transação (por exemplo nos
+// eventos AfterAppend e AfterReplace da entidade) os eventos são gravados somente se a
+// transação for confirmada.
+func Enqueue(ctx context.Context, db Database, events ...Event) error {
+	for _, ev := range events {
+		if err := enqueue(ctx, db, ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func enqueue(ctx context.Context, db Database, ev Event) error {
+	if ev.Type == "" {
+		return errors.New("rdd: event type is required")
+	}
+
+	payload, err := field.NewJSON(ev.Payload)
+	if err != nil {
+		return err
+	}
+
+	o := Use[OutboxEvent]()
+	defer o.Close()
+
+	now := time.Now().UTC()
+
+	o.EntityName.Set(ev.Entity)
+	o.EntityKey.Set(ev.Key)
+	o.Type.Set(ev.Type)
+	o.Payload.Set(payload)
+	o.CreatedAt.Set(now)
+	o.AvailableAt.Set(now)
+
+	return o.Append(ctx, db)
+}
+
+// Publisher publica os eventos do outbox no destino (broker, fila, ...)
+type Publisher interface {
+	Publish(ctx context.Context, event *OutboxEvent) error
+}
+
+// PublisherFunc permite utilizar uma função como Publisher
+type PublisherFunc func(ctx context.Context, event *OutboxEvent) error
+
+func (f PublisherFunc) Publish(ctx context.Context, event *OutboxEvent) error {
+	return f(ctx, event)
+}
+
+// RelayOptions são as opções do Relay
+type RelayOptions struct {
+	// Interval é o intervalo entre as leituras do outbox. O padrão é 1 segundo.
+	Interval time.Duration
+	// BatchSize é a quantidade máxima de eventos por leitura. O padrão é 100.
+	BatchSize int
+	// MaxAttempts é a quantidade máxima de tentativas de publicação. O padrão é 10.
+	MaxAttempts int
+	// RetryDelay é o intervalo até a nova tentativa, dobrado a cada falha. O padrão é 1 segundo.
+	RetryDelay time.Duration
+	// MaxRetryDelay é o intervalo máximo até a nova tentativa. O padrão é 1 hora.
+	MaxRetryDelay time.Duration
+	// OnError recebe os erros de leitura e gravação do outbox durante o Run
+	OnError func(err error)
+}
+
+// Relay lê os eventos pendentes do outbox e os entrega ao Publisher. O evento é marcado
+// como entregue após a publicação, portanto pode ser publicado mais de uma vez se a
+// gravação falhar (at-least-once).
+type Relay struct {
+	db        Database
+	publisher Publisher
+	options   RelayOptions
+}
+
+// NewRelay cria o Relay do outbox
+func NewRelay(db Database, publisher Publisher, options RelayOptions) *Relay {
+	if options.Interval <= 0 {
+		options.Interval = time.Second
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 10
+	}
+	if options.RetryDelay <= 0 {
+		options.RetryDelay = time.Second
+	}
+	if options.MaxRetryDelay <= 0 {
+		options.MaxRetryDelay = time.Hour
+	}
+	return &Relay{db: db, publisher: publisher, options: options}
+}
+
+// Run publica os eventos pendentes a cada intervalo até o cancelamento do contexto
+func (r *Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Poll(ctx); err != nil && r.options.OnError != nil {
+			r.options.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll publica um lote de eventos pendentes e retorna a quantidade de eventos entregues.
+// As falhas de publicação são registradas no evento e a publicação é tentada novamente
+// após o RetryDelay.
+func (r *Relay) Poll(ctx context.Context) (int, error) {
+	events, err := Select[OutboxEvent](r.db, "select id, entity, entity_key, event_type, payload, created_at, available_at, attempts, delivered_at, last_error from rdd_outbox where delivered_at is null and attempts < $1 and available_at <= $2 order by created_at, id limit $3",
+		r.options.MaxAttempts, time.Now().UTC(), r.options.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	defer events.Close()
+
+	delivered := 0
+	for _, ev := range events {
+		if err := ctx.Err(); err != nil {
+			return delivered, err
+		}
+
+		perr := r.publisher.Publish(ctx, ev)
+		if perr != nil {
+			attempts := ev.Attempts.Get() + 1
+			ev.Attempts.Set(attempts)
+			ev.LastError.Set(perr.Error())
+			ev.AvailableAt.Set(time.Now().UTC().Add(r.retryDelay(attempts)))
+		} else {
+			ev.DeliveredAt.Set(time.Now().UTC())
+		}
+
+		if err := ev.Replace(ctx, r.db); err != nil {
+			return delivered, err
+		}
+		if perr == nil {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+// retryDelay retorna o intervalo até a nova tentativa, limitado ao MaxRetryDelay
+func (r *Relay) retryDelay(attempts int64) time.Duration {
+	shift := attempts - 1
+	if shift > 62 || r.options.RetryDelay > r.options.MaxRetryDelay>>shift {
+		return r.options.MaxRetryDelay
+	}
+	return min(r.options.RetryDelay<<shift, r.options.MaxRetryDelay)
+}
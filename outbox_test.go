@@ -0,0 +1,146 @@
+package rdd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dopsilva/rdd/engine"
+	"github.com/dopsilva/rdd/field"
+)
+
+type Fatura struct {
+	Workarea[Fatura] `rdd-table:"faturas"`
+
+	ID    field.Field[int64]   `rdd-column:"id" rdd-primary-key:"true"`
+	Valor field.Field[float64] `rdd-column:"valor"`
+}
+
+// AfterAppend grava o evento de inclusão no outbox, na mesma transação
+func (f *Fatura) AfterAppend(params EventParameters) error {
+	return Enqueue(params.Context, params.Database, EventOf(f, "fatura.incluida", map[string]any{"valor": f.Valor.Get()}))
+}
+
+func init() {
+	Register[Fatura]()
+}
+
+func TestOutbox(t *testing.T) {
+	db, err := Connect(engine.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.CreateTable(schemaOf[Fatura](), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateOutboxTable(db); err != nil {
+		t.Fatal(err)
+	}
+
+	pending := func() int {
+		var count int
+		if err := db.QueryRow("select count(*) from rdd_outbox where delivered_at is null").Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+
+	appendFatura := func(id int64, commit bool) {
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		f := Use[Fatura]()
+		defer f.Close()
+		f.ID.Set(id)
+		f.Valor.Set(10.5)
+		if err := f.Append(testContext, tx); err != nil {
+			t.Fatal(err)
+		}
+		if commit {
+			err = tx.Commit(testContext)
+		} else {
+			err = tx.Rollback()
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// o evento é descartado com a transação
+	appendFatura(1, false)
+	if n := pending(); n != 0 {
+		t.Fatalf("esperado nenhum evento obtido %d", n)
+	}
+
+	appendFatura(2, true)
+	if n := pending(); n != 1 {
+		t.Fatalf("esperado 1 evento obtido %d", n)
+	}
+
+	published := make([]string, 0)
+	fail := true
+	publisher := PublisherFunc(func(ctx context.Context, ev *OutboxEvent) error {
+		if fail {
+			return errors.New("broker indisponível")
+		}
+		published = append(published, ev.Type.Get()+":"+ev.EntityName.Get()+":"+ev.EntityKey.Get())
+		return nil
+	})
+
+	relay := NewRelay(db, publisher, RelayOptions{RetryDelay: time.Millisecond})
+
+	// a falha é registrada e a publicação é tentada novamente após o intervalo
+	if n, err := relay.Poll(testContext); err != nil || n != 0 {
+		t.Fatalf("esperado nenhum evento entregue obtido %d (%v)", n, err)
+	}
+
+	var attempts int64
+	var lastError string
+	if err := db.QueryRow("select attempts, last_error from rdd_outbox").Scan(&attempts, &lastError); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 1 || lastError != "broker indisponível" {
+		t.Fatalf("falha não registrada: %d %q", attempts, lastError)
+	}
+
+	fail = false
+	time.Sleep(5 * time.Millisecond)
+
+	if n, err := relay.Poll(testContext); err != nil || n != 1 {
+		t.Fatalf("esperado 1 evento entregue obtido %d (%v)", n, err)
+	}
+	if len(published) != 1 || published[0] != "fatura.incluida:Fatura:2" {
+		t.Fatalf("publicação inesperada %v", published)
+	}
+	if n := pending(); n != 0 {
+		t.Fatalf("esperado nenhum evento pendente obtido %d", n)
+	}
+
+	// o evento entregue não é publicado novamente
+	if n, err := relay.Poll(testContext); err != nil || n != 0 {
+		t.Fatalf("esperado nenhum evento entregue obtido %d (%v)", n, err)
+	}
+}
+
+func TestOutboxRetryDelay(t *testing.T) {
+	r := NewRelay(nil, nil, RelayOptions{RetryDelay: time.Second, MaxAttempts: 100})
+
+	for _, c := range []struct {
+		attempts int64
+		delay    time.Duration
+	}{
+		{1, time.Second},
+		{3, 4 * time.Second},
+		{13, time.Hour},
+		{40, time.Hour},
+		{100, time.Hour},
+	} {
+		if d := r.retryDelay(c.attempts); d != c.delay {
+			t.Fatalf("tentativa %d: esperado %s obtido %s", c.attempts, c.delay, d)
+		}
+	}
+}
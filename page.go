@@ -0,0 +1,350 @@
+package rdd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/dopsilva/rdd/builder"
+	"github.com/dopsilva/rdd/field"
+)
+
+// ErrInvalidCursor é retornado quando o cursor não pertence à paginação
+var ErrInvalidCursor = errors.New("rdd: invalid cursor")
+
+// PageRequest é a requisição da página.
+//
+// Com o Number, a paginação é por offset e a página contém o total de registros.
+// Sem o Number, a paginação é por keyset a partir do Cursor (Page.Next ou Page.Prev),
+// ordenada pelo OrderBy seguido da primary key. Sem o Cursor é retornada a primeira página.
+// As colunas nullable não são aceitas na ordenação do keyset.
+type PageRequest struct {
+	// Size é a quantidade de registros da página. O padrão é 20.
+	Size  int
+	Where []builder.Condition
+	// OrderBy são as colunas da ordenação, opcionalmente seguidas de asc ou desc
+	OrderBy []string
+	// Number é a página da paginação por offset, a partir de 1
+	Number int
+	// Cursor é o cursor da paginação por keyset
+	Cursor string
+}
+
+// Page é a página de entidades. Importante que após o uso, a página seja fechada com Close.
+type Page[T any] struct {
+	Items   Resultset[T]
+	HasNext bool
+	HasPrev bool
+	// Next e Prev são os cursores das páginas seguinte e anterior na paginação por keyset
+	Next string
+	Prev string
+	// Number e Total são a página e o total de registros na paginação por offset
+	Number int
+	Total  int64
+}
+
+// Close fecha as entidades da página
+func (p *Page[T]) Close() {
+	p.Items.Close()
+}
+
+// cursor é o conteúdo do cursor: a ordenação e os valores das colunas do registro limite
+type cursor struct {
+	Prev   bool              `json:"p,omitempty"`
+	Order  []string          `json:"o"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// Paginate retorna a página das entidades T
+func Paginate[T any](db Database, request PageRequest) (*Page[T], error) {
+	if request.Size <= 0 {
+		request.Size = 20
+	}
+
+	e := Use[T]()
+	defer any(e).(Workarea[T]).Close()
+
+	w := any(e).(Workarea[T]).instance()
+
+	if request.Number > 0 {
+		if request.Cursor != "" {
+			return nil, errors.New("rdd: page number and cursor are mutually exclusive")
+		}
+		return offsetPage[T](db, w, request)
+	}
+	return keysetPage[T](db, w, request)
+}
+
+func offsetPage[T any](db Database, w *workarea[T], request PageRequest) (*Page[T], error) {
+	// total de registros
+	q, args, err := db.Builder().Select(*w.schema, &builder.SelectOptions{Columns: w.pageColumns()[:1], Where: request.Where})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page[T]{Number: request.Number}
+	if err := db.QueryRow("select count(*) from ("+q+") as t", args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	q, args, err = db.Builder().Select(*w.schema, &builder.SelectOptions{
+		Columns: w.pageColumns(),
+		Where:   request.Where,
+		OrderBy: request.OrderBy,
+		Limit:   request.Size,
+		Offset:  (request.Number - 1) * request.Size,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if page.Items, err = Select[T](db, q, args...); err != nil {
+		return nil, err
+	}
+
+	page.HasPrev = request.Number > 1
+	page.HasNext = int64(request.Number*request.Size) < page.Total
+
+	return page, nil
+}
+
+func keysetPage[T any](db Database, w *workarea[T], request PageRequest) (*Page[T], error) {
+	order, err := w.keysetOrder(request.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range order {
+		column, _, _ := builder.ParseOrder(o)
+		if textDecimal(db, w.fields[column].Schema) {
+			return nil, fmt.Errorf("rdd: keyset pagination is not supported on decimal column %s, stored as text on %s", column, db.Engine())
+		}
+	}
+
+	c := cursor{Order: order}
+	where := slices.Clone(request.Where)
+
+	if request.Cursor != "" {
+		if c, err = decodeCursor(request.Cursor, order); err != nil {
+			return nil, err
+		}
+		cond, err := w.keysetCondition(c)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, cond)
+	}
+
+	// a página anterior é lida na ordem inversa
+	orderBy := slices.Clone(order)
+	if c.Prev {
+		for i, o := range orderBy {
+			column, desc, _ := builder.ParseOrder(o)
+			if desc {
+				orderBy[i] = column
+			} else {
+				orderBy[i] = column + " desc"
+			}
+		}
+	}
+
+	q, args, err := db.Builder().Select(*w.schema, &builder.SelectOptions{
+		Columns: w.pageColumns(),
+		Where:   where,
+		OrderBy: orderBy,
+		Limit:   request.Size + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := Select[T](db, q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// o registro adicional indica a existência de mais registros na direção da leitura
+	more := len(items) > request.Size
+	if more {
+		items[request.Size:].Close()
+		items = items[:request.Size]
+	}
+
+	page := &Page[T]{Items: items}
+	if c.Prev {
+		slices.Reverse(page.Items)
+		page.HasPrev, page.HasNext = more, true
+	} else {
+		page.HasPrev, page.HasNext = request.Cursor != "", more
+	}
+
+	if len(page.Items) > 0 {
+		if page.HasNext {
+			if page.Next, err = encodeCursor(page.Items[len(page.Items)-1], order, false); err != nil {
+				return nil, err
+			}
+		}
+		if page.HasPrev {
+			if page.Prev, err = encodeCursor(page.Items[0], order, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return page, nil
+}
+
+// pageColumns retorna as colunas da entidade ordenadas pelo nome
+func (w *workarea[T]) pageColumns() []string {
+	columns := make([]string, 0, len(w.fields))
+	for k := range w.fields {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// keysetOrder retorna a ordenação do keyset: as colunas informadas seguidas da primary key
+func (w *workarea[T]) keysetOrder(orderBy []string) ([]string, error) {
+	order := make([]string, 0, len(orderBy)+1)
+	columns := make([]string, 0, len(orderBy)+1)
+
+	for _, o := range orderBy {
+		column, desc, err := builder.ParseOrder(o)
+		if err != nil {
+			return nil, err
+		}
+		fi, ok := w.fields[column]
+		if !ok {
+			return nil, fmt.Errorf("rdd: column %s not defined on %s", column, w.Entity())
+		}
+		// os registros com null não são comparáveis pelo cursor
+		if fi.Schema.Nullable {
+			return nil, fmt.Errorf("rdd: keyset pagination is not supported on nullable column %s", column)
+		}
+		if desc {
+			order = append(order, column+" desc")
+		} else {
+			order = append(order, column)
+		}
+		columns = append(columns, column)
+	}
+
+	pk := make([]string, 0)
+	for k, fi := range w.fields {
+		if fi.Schema.PrimaryKey {
+			pk = append(pk, k)
+		}
+	}
+	if len(pk) == 0 {
+		return nil, fmt.Errorf("rdd: %s has no primary key", w.Entity())
+	}
+	sort.Strings(pk)
+
+	// a primary key desempata os registros com os mesmos valores
+	for _, k := range pk {
+		if !slices.Contains(columns, k) {
+			order = append(order, k)
+		}
+	}
+
+	return order, nil
+}
+
+// keysetCondition cria a condição dos registros após o cursor:
+// (a > $1) or (a = $1 and b > $2) ...
+func (w *workarea[T]) keysetCondition(c cursor) (builder.Condition, error) {
+	values := make([]any, len(c.Order))
+	for i, o := range c.Order {
+		column, _, _ := builder.ParseOrder(o)
+		v, err := w.cursorValue(column, c.Values[i])
+		if err != nil {
+			return builder.Condition{}, err
+		}
+		values[i] = sensitiveValue(w.fields[column], v)
+	}
+
+	cond := builder.Condition{}
+	for i, o := range c.Order {
+		group := make([]builder.Condition, 0, i+1)
+		for j := 0; j < i; j++ {
+			column, _, _ := builder.ParseOrder(c.Order[j])
+			group = append(group, builder.Condition{Column: column, Operator: "=", Values: []any{values[j]}})
+		}
+
+		column, desc, _ := builder.ParseOrder(o)
+		op := ">"
+		if desc != c.Prev {
+			op = "<"
+		}
+		group = append(group, builder.Condition{Column: column, Operator: op, Values: []any{values[i]}})
+
+		cond.Any = append(cond.Any, group)
+	}
+
+	return cond, nil
+}
+
+// cursorValue decodifica o valor da coluna do cursor no tipo do campo
+func (w *workarea[T]) cursorValue(column string, raw json.RawMessage) (any, error) {
+	if string(raw) == "null" {
+		return nil, fmt.Errorf("rdd: cursor column %s is null", column)
+	}
+
+	if t, ok := w.fields[column].Addr.(field.Typed); ok {
+		v := reflect.New(t.Type())
+		if err := json.Unmarshal(raw, v.Interface()); err == nil {
+			return v.Elem().Interface(), nil
+		}
+	}
+
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return v, nil
+}
+
+func encodeCursor[T any](e *T, order []string, prev bool) (string, error) {
+	w := any(e).(Workarea[T]).instance()
+
+	c := cursor{Prev: prev, Order: order, Values: make([]json.RawMessage, len(order))}
+	for i, o := range order {
+		column, _, _ := builder.ParseOrder(o)
+		v, err := fieldValue(w.fields[column])
+		if err != nil {
+			return "", err
+		}
+		if c.Values[i], err = json.Marshal(v); err != nil {
+			return "", err
+		}
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCursor(s string, order []string) (cursor, error) {
+	var c cursor
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	// o cursor deve ser utilizado com a mesma ordenação
+	if !slices.Equal(c.Order, order) || len(c.Values) != len(order) {
+		return c, ErrInvalidCursor
+	}
+
+	return c, nil
+}
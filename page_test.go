@@ -0,0 +1,143 @@
+package rdd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dopsilva/rdd/builder"
+	"github.com/dopsilva/rdd/engine"
+	"github.com/dopsilva/rdd/field"
+)
+
+type Cidade struct {
+	Workarea[Cidade] `rdd-table:"cidades"`
+
+	ID        field.Field[int64]  `rdd-column:"id" rdd-primary-key:"true"`
+	Nome      field.Field[string] `rdd-column:"nome"`
+	Populacao field.Field[int64]  `rdd-column:"populacao"`
+}
+
+func init() {
+	Register[Cidade]()
+}
+
+func pageIDs(p *Page[Cidade]) []int64 {
+	ids := make([]int64, len(p.Items))
+	for i, c := range p.Items {
+		ids[i] = c.ID.Get()
+	}
+	return ids
+}
+
+func TestPaginate(t *testing.T) {
+	db, err := Connect(engine.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.CreateTable(schemaOf[Cidade](), nil); err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range []int64{500, 300, 300, 800, 100, 300, 700} {
+		if _, err := db.Exec("insert into cidades (id, nome, populacao) values ($1, $2, $3)", i+1, fmt.Sprintf("cidade %d", i+1), p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// paginação por offset
+	p, err := Paginate[Cidade](db, PageRequest{Size: 3, Number: 2, OrderBy: []string{"id"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(pageIDs(p), []int64{4, 5, 6}) || p.Total != 7 || !p.HasPrev || !p.HasNext {
+		t.Fatalf("página inesperada %v %+v", pageIDs(p), p)
+	}
+	p.Close()
+
+	p, err = Paginate[Cidade](db, PageRequest{Size: 3, Number: 3, OrderBy: []string{"id"}, Where: []builder.Condition{{Column: "populacao", Operator: ">=", Values: []any{300}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Total != 6 || len(p.Items) != 0 || p.HasNext || !p.HasPrev {
+		t.Fatalf("página inesperada %v %+v", pageIDs(p), p)
+	}
+	p.Close()
+
+	// paginação por keyset, com desempate pela primary key
+	request := PageRequest{Size: 3, OrderBy: []string{"populacao desc"}}
+	pages := make([][]int64, 0)
+	cursors := make([]string, 0)
+	for {
+		p, err := Paginate[Cidade](db, request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, pageIDs(p))
+		cursors = append(cursors, p.Prev)
+		p.Close()
+
+		if !p.HasNext {
+			break
+		}
+		request.Cursor = p.Next
+	}
+
+	expected := [][]int64{{4, 7, 1}, {2, 3, 6}, {5}}
+	if len(pages) != len(expected) {
+		t.Fatalf("esperado %v obtido %v", expected, pages)
+	}
+	for i := range expected {
+		if !slices.Equal(pages[i], expected[i]) {
+			t.Fatalf("esperado %v obtido %v", expected, pages)
+		}
+	}
+	if cursors[0] != "" {
+		t.Fatal("não esperado cursor anterior na primeira página")
+	}
+
+	// página anterior a partir da última
+	request.Cursor = cursors[2]
+	p, err = Paginate[Cidade](db, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(pageIDs(p), []int64{2, 3, 6}) || !p.HasPrev || !p.HasNext {
+		t.Fatalf("página anterior inesperada %v", pageIDs(p))
+	}
+	p.Close()
+
+	// o cursor pertence à ordenação
+	request.OrderBy = []string{"nome"}
+	if _, err := Paginate[Cidade](db, request); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("esperado %v obtido %v", ErrInvalidCursor, err)
+	}
+}
+
+func TestPaginateNullable(t *testing.T) {
+	db, err := Connect(engine.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.CreateTable(schemaOf[Documento](), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// a coluna nullable é aceita apenas na paginação por offset
+	request := PageRequest{Size: 2, OrderBy: []string{"removido_em"}}
+	if _, err := Paginate[Documento](db, request); err == nil || !strings.Contains(err.Error(), "nullable column removido_em") {
+		t.Fatalf("esperado erro com coluna nullable obtido %v", err)
+	}
+
+	request.Number = 1
+	p, err := Paginate[Documento](db, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Close()
+}
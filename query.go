@@ -0,0 +1,156 @@
+package rdd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Query executa a query no banco de dados retornando as linhas em R, que pode ser:
+//
+//   - uma estrutura: as colunas são associadas aos campos pela tag rdd-column ou, sem a
+//     tag, pelo nome do campo, sem diferenciar maiúsculas e minúsculas
+//   - map[string]any: as colunas são as chaves do mapa
+//   - um valor escalar (string, int64, time.Time, sql.Null*, ...): a query deve retornar
+//     uma única coluna
+//
+// Retorna erro se alguma coluna não tiver um campo associado na estrutura.
+func Query[R any](db Database, q string, args ...any) (_ []R, err error) {
+	ctx, done := startOperation(context.Background(), db, reflect.TypeOf((*R)(nil)).Elem().String(), "query")
+	defer done(&err)
+
+	rows, err := queryContext(ctx, db, &Call{Query: q, Args: args})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return make([]R, 0), nil
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRows[R](rows)
+}
+
+// scanRows lê as linhas em R
+func scanRows[R any](rows *sql.Rows) ([]R, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	rt := reflect.TypeOf((*R)(nil)).Elem()
+	isMap := rt.Kind() == reflect.Map
+	if isMap && (rt.Key().Kind() != reflect.String || rt.Elem().Kind() != reflect.Interface) {
+		return nil, fmt.Errorf("rdd: %s is not supported, use map[string]any", rt)
+	}
+	if !isMap && isScalar(rt) && len(columns) != 1 {
+		return nil, fmt.Errorf("rdd: query returns %d columns, %s expects one", len(columns), rt)
+	}
+
+	res := make([]R, 0)
+	for rows.Next() {
+		var r R
+
+		switch {
+		case isMap:
+			values := make([]any, len(columns))
+			dest := make([]any, len(columns))
+			for i := range values {
+				dest[i] = &values[i]
+			}
+			if err := rows.Scan(dest...); err != nil {
+				return nil, err
+			}
+
+			m := make(map[string]any, len(columns))
+			for i, c := range columns {
+				// o driver pode reutilizar o buffer dos []byte
+				if b, ok := values[i].([]byte); ok {
+					values[i] = append([]byte(nil), b...)
+				}
+				m[c] = values[i]
+			}
+			reflect.ValueOf(&r).Elem().Set(reflect.ValueOf(m))
+		case isScalar(rt):
+			if err := rows.Scan(&r); err != nil {
+				return nil, err
+			}
+		default:
+			dest, err := structFieldsAddr(&r, columns)
+			if err != nil {
+				return nil, err
+			}
+			if err := rows.Scan(dest...); err != nil {
+				return nil, err
+			}
+		}
+
+		res = append(res, r)
+	}
+
+	return res, rows.Err()
+}
+
+// isScalar verifica se o tipo é lido de uma única coluna: tipos que não são estruturas,
+// time.Time e estruturas que implementam sql.Scanner (sql.Null*, field.Field, ...)
+func isScalar(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return true
+	}
+	return reflect.PointerTo(t).Implements(reflect.TypeOf((*sql.Scanner)(nil)).Elem())
+}
+
+// structFieldsAddr retorna o endereço dos campos da estrutura associados às colunas
+// pela tag rdd-column ou, sem a tag, pelo nome do campo
+func structFieldsAddr(dest any, columns []string) ([]any, error) {
+	rv := reflect.ValueOf(dest).Elem()
+	rt := rv.Type()
+	if rt.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("rdd: %s is not a struct", rt)
+	}
+
+	fields := make(map[string]int, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		if !rt.Field(i).IsExported() {
+			continue
+		}
+		name, ok := rt.Field(i).Tag.Lookup("rdd-column")
+		if !ok {
+			name = rt.Field(i).Name
+		}
+		fields[strings.ToLower(name)] = i
+	}
+
+	addr := make([]any, len(columns))
+	unmatched := make([]string, 0)
+	for i, c := range columns {
+		f, ok := fields[strings.ToLower(c)]
+		if !ok {
+			unmatched = append(unmatched, c)
+			continue
+		}
+		addr[i] = rv.Field(f).Addr().Interface()
+	}
+	if len(unmatched) > 0 {
+		return nil, fmt.Errorf("rdd: columns %s have no field on %s", strings.Join(unmatched, ", "), rt)
+	}
+	return addr, nil
+}
+
+// unmatchedColumns retorna o erro com as colunas sem campo na entidade
+func (w *workarea[T]) unmatchedColumns(columns []string) error {
+	unmatched := make([]string, 0)
+	for _, c := range columns {
+		if _, ok := w.fields[c]; !ok {
+			unmatched = append(unmatched, c)
+		}
+	}
+	return fmt.Errorf("rdd: columns %s have no field on %s", strings.Join(unmatched, ", "), w.Entity())
+}
@@ -0,0 +1,77 @@
+package rdd
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/dopsilva/rdd/engine"
+	"github.com/dopsilva/rdd/schema"
+)
+
+func TestQuery(t *testing.T) {
+	db, err := Connect(engine.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, e := range []*schema.Table{schemaOf[Usuario](), schemaOf[Post]()} {
+		if err := db.CreateTable(e, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, q := range []string{
+		"insert into usuarios (id, email, nome, incluido_em) values ('u1', 'a@a.com', 'Ana', '2024-01-01')",
+		"insert into posts (id, titulo, autor_id) values (1, 'um', 'u1')",
+		"insert into posts (id, titulo, autor_id) values (2, 'dois', 'u1')",
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// estrutura com a tag rdd-column ou o nome do campo
+	type postAutor struct {
+		Titulo string `rdd-column:"titulo"`
+		Autor  string `rdd-column:"autor"`
+		Email  sql.NullString
+	}
+
+	r, err := Query[postAutor](db, "select p.titulo, u.nome as autor, u.email from posts p join usuarios u on u.id = p.autor_id order by p.id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r) != 2 || r[0].Titulo != "um" || r[1].Autor != "Ana" || r[1].Email.String != "a@a.com" {
+		t.Fatalf("resultado inesperado %v", r)
+	}
+
+	// map
+	m, err := Query[map[string]any](db, "select count(*) as posts, max(titulo) as ultimo from posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 1 || m[0]["posts"] != int64(2) || m[0]["ultimo"] != "um" {
+		t.Fatalf("resultado inesperado %v", m)
+	}
+
+	// escalar
+	titulos, err := Query[string](db, "select titulo from posts where autor_id = $1 order by id", "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(titulos, ",") != "um,dois" {
+		t.Fatalf("resultado inesperado %v", titulos)
+	}
+	if _, err := Query[string](db, "select id, titulo from posts"); err == nil {
+		t.Fatal("esperado erro com mais de uma coluna no escalar")
+	}
+
+	// colunas sem campo
+	if _, err := Query[postAutor](db, "select titulo, id from posts"); err == nil || !strings.Contains(err.Error(), "columns id have no field") {
+		t.Fatalf("esperado erro com coluna sem campo obtido %v", err)
+	}
+	if _, err := Select[Post](db, "select id, titulo, 1 as extra from posts"); err == nil || !strings.Contains(err.Error(), "columns extra have no field on Post") {
+		t.Fatalf("esperado erro com coluna sem campo obtido %v", err)
+	}
+}
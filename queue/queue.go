@@ -0,0 +1,307 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dopsilva/rdd"
+	"github.com/dopsilva/rdd/builder"
+	"github.com/dopsilva/rdd/engine"
+	"github.com/dopsilva/rdd/field"
+	"github.com/google/uuid"
+)
+
+// Situações do job
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusDead    = "dead"
+)
+
+// Job é o job gravado na fila
+type Job struct {
+	rdd.Workarea[Job] `rdd-table:"rdd_jobs"`
+
+	ID          field.Field[string]       `rdd-column:"id" rdd-primary-key:"true" rdd-auto-generated:"true" rdd-default:"new_uuid"`
+	Queue       field.Field[string]       `rdd-column:"queue"`
+	Payload     field.Field[field.JSON]   `rdd-column:"payload"`
+	Status      field.Field[string]       `rdd-column:"status" rdd-default:"'pending'"`
+	Attempts    field.Field[int64]        `rdd-column:"attempts" rdd-default:"0"`
+	MaxAttempts field.Field[int64]        `rdd-column:"max_attempts"`
+	RunAt       field.Field[time.Time]    `rdd-column:"run_at"`
+	LockedUntil field.Nullable[time.Time] `rdd-column:"locked_until"`
+	LockedBy    field.Field[string]       `rdd-column:"locked_by" rdd-default:"''"`
+	LastError   field.Field[string]       `rdd-column:"last_error" rdd-default:"''"`
+	CreatedAt   field.Field[time.Time]    `rdd-column:"created_at"`
+	CompletedAt field.Nullable[time.Time] `rdd-column:"completed_at"`
+}
+
+func init() {
+	rdd.Register[Job]()
+}
+
+// Decode decodifica o payload do job
+func (j *Job) Decode(v any) error {
+	return j.Payload.Get().Unmarshal(v)
+}
+
+const jobColumns = "id, queue, payload, status, attempts, max_attempts, run_at, locked_until, locked_by, last_error, created_at, completed_at"
+
+// CreateTable cria a tabela dos jobs
+func CreateTable(db rdd.Database, options *builder.CreateTableOptions) error {
+	j := rdd.Use[Job]()
+	defer j.Close()
+
+	return db.CreateTable(j.Schema(), options)
+}
+
+// EnqueueOption configura o job incluído na fila
+type EnqueueOption func(j *Job)
+
+// Delay posterga a execução do job
+func Delay(d time.Duration) EnqueueOption {
+	return func(j *Job) {
+		j.RunAt.Set(j.RunAt.Get().Add(d))
+	}
+}
+
+// MaxAttempts define a quantidade máxima de execuções do job. O padrão é 5.
+func MaxAttempts(n int64) EnqueueOption {
+	return func(j *Job) {
+		j.MaxAttempts.Set(n)
+	}
+}
+
+// Enqueue inclui o job na fila e retorna o seu id. Dentro de uma transação, o job
+// somente fica disponível para os workers após o Commit. Retorna erro se o job não
+// for gravado.
+func Enqueue(ctx context.Context, db rdd.Database, queue string, payload any, options ...EnqueueOption) (string, error) {
+	if queue == "" {
+		return "", errors.New("queue: queue name is required")
+	}
+
+	p, err := field.NewJSON(payload)
+	if err != nil {
+		return "", err
+	}
+
+	j := rdd.Use[Job]()
+	defer j.Close()
+
+	now := time.Now().UTC()
+
+	// a situação é definida no job, pois o default da coluna não é lido após a inclusão
+	j.Queue.Set(queue)
+	j.Payload.Set(p)
+	j.Status.Set(StatusPending)
+	j.MaxAttempts.Set(5)
+	j.RunAt.Set(now)
+	j.CreatedAt.Set(now)
+
+	for _, o := range options {
+		o(j)
+	}
+
+	if err := j.Append(ctx, db); err != nil {
+		return "", err
+	}
+
+	return j.ID.Get(), nil
+}
+
+// DeadLetters retorna os jobs da fila que excederam a quantidade máxima de execuções.
+// Importante fechar o resultado com Close.
+func DeadLetters(db rdd.Database, queue string) (rdd.Resultset[Job], error) {
+	return rdd.Select[Job](db, "select "+jobColumns+" from rdd_jobs where queue = $1 and status = $2 order by created_at", queue, StatusDead)
+}
+
+// Requeue devolve o job descartado para a fila, zerando as execuções
+func Requeue(ctx context.Context, db rdd.Database, id string) error {
+	r, err := db.Exec("update rdd_jobs set status = $1, attempts = 0, run_at = $2, last_error = '' where id = $3 and status = $4",
+		StatusPending, time.Now().UTC(), id, StatusDead)
+	if err != nil {
+		return err
+	}
+	if n, err := r.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("queue: job %s is not dead", id)
+	}
+	return nil
+}
+
+// Handler executa o job. O erro (ou panic) agenda uma nova execução, até a quantidade
+// máxima de execuções do job, quando o job é descartado (dead letter).
+type Handler func(ctx context.Context, job *Job) error
+
+// Options são as opções do Worker
+type Options struct {
+	// Workers é a quantidade de jobs executados simultaneamente. O padrão é 1.
+	Workers int
+	// PollInterval é o intervalo entre as leituras da fila vazia. O padrão é 1 segundo.
+	PollInterval time.Duration
+	// VisibilityTimeout é o tempo em que o job fica reservado para o worker. Após esse
+	// tempo o job pode ser executado por outro worker. É também o prazo do contexto do
+	// Handler. O padrão é 30 segundos.
+	VisibilityTimeout time.Duration
+	// Backoff retorna o intervalo até a próxima execução após a falha. O padrão é
+	// 1 segundo, dobrado a cada execução, limitado a 1 hora.
+	Backoff func(attempts int64) time.Duration
+	// OnError recebe os erros de leitura e gravação da fila e os erros dos jobs
+	OnError func(job *Job, err error)
+}
+
+// Worker executa os jobs de uma fila
+type Worker struct {
+	db      rdd.Database
+	queue   string
+	handler Handler
+	options Options
+}
+
+// NewWorker cria o worker da fila
+func NewWorker(db rdd.Database, queue string, handler Handler, options Options) *Worker {
+	if options.Workers <= 0 {
+		options.Workers = 1
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = time.Second
+	}
+	if options.VisibilityTimeout <= 0 {
+		options.VisibilityTimeout = 30 * time.Second
+	}
+	if options.Backoff == nil {
+		options.Backoff = backoff
+	}
+	return &Worker{db: db, queue: queue, handler: handler, options: options}
+}
+
+func backoff(attempts int64) time.Duration {
+	if attempts > 12 {
+		return time.Hour
+	}
+	return min(time.Second<<(attempts-1), time.Hour)
+}
+
+// Run executa os jobs da fila até o cancelamento do contexto. Após o cancelamento nenhum
+// job é reservado e o Run aguarda o término dos jobs em execução.
+func (w *Worker) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	for i := 0; i < w.options.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx)
+		}()
+	}
+
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+func (w *Worker) loop(ctx context.Context) {
+	for ctx.Err() == nil {
+		ok, err := w.Work(ctx)
+		if err != nil {
+			w.report(nil, err)
+		}
+		if ok && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(w.options.PollInterval):
+		}
+	}
+}
+
+// Work reserva e executa um job disponível da fila. Retorna falso se a fila estiver vazia.
+func (w *Worker) Work(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	jobs, err := w.claim()
+	if err != nil || len(jobs) == 0 {
+		return false, err
+	}
+	defer jobs.Close()
+
+	j := jobs[0]
+
+	// o job excedeu as execuções sem concluir (por exemplo, o worker foi encerrado)
+	if j.Attempts.Get() > j.MaxAttempts.Get() {
+		return true, w.fail(j, errors.New("queue: visibility timeout exceeded"))
+	}
+
+	// o job em execução não é interrompido pelo cancelamento do contexto do worker
+	hctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), w.options.VisibilityTimeout)
+	defer cancel()
+
+	if err := w.execute(hctx, j); err != nil {
+		w.report(j, err)
+		return true, w.fail(j, err)
+	}
+
+	_, err = w.db.Exec("update rdd_jobs set status = $1, completed_at = $2, locked_until = null where id = $3 and locked_by = $4",
+		StatusDone, time.Now().UTC(), j.ID.Get(), j.LockedBy.Get())
+
+	return true, err
+}
+
+func (w *Worker) execute(ctx context.Context, j *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("queue: job panicked: %v", r)
+		}
+	}()
+	return w.handler(ctx, j)
+}
+
+// claim reserva o próximo job disponível da fila: pendente e agendado, ou em execução
+// com o tempo de reserva expirado
+func (w *Worker) claim() (rdd.Resultset[Job], error) {
+	switch w.db.Engine() {
+	case rdd.SQLite:
+		// o update é atômico, pois o SQLite serializa as gravações
+	case rdd.Cockroach:
+	default:
+		return nil, fmt.Errorf("queue: engine %s not supported", w.db.Engine())
+	}
+
+	// os jobs reservados por outros workers são ignorados
+	lock, err := builder.Lock{Mode: builder.LockForUpdate, SkipLocked: true}.Clause(engine.Engine(w.db.Engine()))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	return rdd.Select[Job](w.db, "update rdd_jobs set status = $1, attempts = attempts + 1, locked_by = $2, locked_until = $3 "+
+		"where id = (select id from rdd_jobs where queue = $4 and ((status = $5 and run_at <= $6) or (status = $1 and locked_until < $6)) "+
+		"order by run_at, created_at limit 1"+lock+") returning "+jobColumns,
+		StatusRunning, uuid.NewString(), now.Add(w.options.VisibilityTimeout), w.queue, StatusPending, now)
+}
+
+// fail agenda a próxima execução do job ou o descarta se excedeu as execuções
+func (w *Worker) fail(j *Job, cause error) error {
+	status, runAt := StatusPending, time.Now().UTC().Add(w.options.Backoff(j.Attempts.Get()))
+	if j.Attempts.Get() >= j.MaxAttempts.Get() {
+		status, runAt = StatusDead, j.RunAt.Get()
+	}
+
+	_, err := w.db.Exec("update rdd_jobs set status = $1, run_at = $2, last_error = $3, locked_until = null where id = $4 and locked_by = $5",
+		status, runAt, cause.Error(), j.ID.Get(), j.LockedBy.Get())
+	return err
+}
+
+func (w *Worker) report(j *Job, err error) {
+	if w.options.OnError != nil {
+		w.options.OnError(j, err)
+	}
+}
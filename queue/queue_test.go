@@ -0,0 +1,239 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dopsilva/rdd"
+	"github.com/dopsilva/rdd/engine"
+)
+
+type email struct {
+	To string `json:"to"`
+}
+
+func connect(t *testing.T) rdd.Database {
+	db, err := rdd.Connect(engine.SQLite, "file:"+filepath.Join(t.TempDir(), "queue.db")+"?_busy_timeout=5000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := CreateTable(db, nil); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func count(t *testing.T, db rdd.Database, status string) int {
+	var n int
+	if err := db.QueryRow("select count(*) from rdd_jobs where status = $1", status).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestEnqueue(t *testing.T) {
+	ctx := context.Background()
+	db := connect(t)
+
+	// o job incluído na transação desfeita não é gravado
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Enqueue(ctx, tx, "emails", email{To: "a@a.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Enqueue(ctx, db, "emails", email{To: "b@b.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Enqueue(ctx, db, "emails", email{To: "c@c.com"}, Delay(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := make([]string, 0)
+	w := NewWorker(db, "emails", func(ctx context.Context, job *Job) error {
+		var e email
+		if err := job.Decode(&e); err != nil {
+			return err
+		}
+		sent = append(sent, e.To)
+		return nil
+	}, Options{})
+
+	for {
+		ok, err := w.Work(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+	}
+
+	// o job postergado ainda não está disponível
+	if len(sent) != 1 || sent[0] != "b@b.com" {
+		t.Fatalf("execução inesperada %v", sent)
+	}
+	if count(t, db, StatusDone) != 1 || count(t, db, StatusPending) != 1 {
+		t.Fatal("situação inesperada dos jobs")
+	}
+}
+
+func TestEnqueueError(t *testing.T) {
+	db, err := rdd.Connect(engine.SQLite, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// sem a tabela dos jobs
+	if id, err := Enqueue(context.Background(), db, "emails", email{To: "a@a.com"}); err == nil || id != "" {
+		t.Fatalf("esperado erro sem a tabela obtido %q (%v)", id, err)
+	}
+
+	// tabela sem as colunas dos jobs
+	if _, err := db.Exec("create table rdd_jobs (id text primary key, queue text not null)"); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := Enqueue(context.Background(), db, "emails", email{To: "a@a.com"}); err == nil || id != "" {
+		t.Fatalf("esperado erro com a tabela inválida obtido %q (%v)", id, err)
+	}
+}
+
+func TestRetryAndDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	db := connect(t)
+
+	id, err := Enqueue(ctx, db, "emails", email{To: "a@a.com"}, MaxAttempts(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failures := 0
+	w := NewWorker(db, "emails", func(ctx context.Context, job *Job) error {
+		failures++
+		if failures == 2 {
+			panic("smtp")
+		}
+		return errors.New("smtp indisponível")
+	}, Options{Backoff: func(int64) time.Duration { return 0 }})
+
+	for i := 0; i < 3; i++ {
+		if _, err := w.Work(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if failures != 2 {
+		t.Fatalf("esperado 2 execuções obtido %d", failures)
+	}
+
+	dead, err := DeadLetters(db, "emails")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dead.Close()
+	if len(dead) != 1 || dead[0].ID.Get() != id || dead[0].LastError.Get() != "queue: job panicked: smtp" {
+		t.Fatalf("dead letter inesperado %v", dead)
+	}
+
+	if err := Requeue(ctx, db, id); err != nil {
+		t.Fatal(err)
+	}
+	if count(t, db, StatusPending) != 1 {
+		t.Fatal("esperado job pendente após o requeue")
+	}
+	if err := Requeue(ctx, db, id); err == nil {
+		t.Fatal("esperado erro no requeue de job pendente")
+	}
+}
+
+func TestVisibilityTimeout(t *testing.T) {
+	ctx := context.Background()
+	db := connect(t)
+
+	if _, err := Enqueue(ctx, db, "emails", email{To: "a@a.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// o worker reserva o job e não o conclui
+	w := NewWorker(db, "emails", nil, Options{VisibilityTimeout: time.Millisecond})
+	jobs, err := w.claim()
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("esperado 1 job reservado obtido %d (%v)", len(jobs), err)
+	}
+	jobs.Close()
+
+	executed := 0
+	w = NewWorker(db, "emails", func(ctx context.Context, job *Job) error {
+		executed++
+		return nil
+	}, Options{})
+
+	time.Sleep(5 * time.Millisecond)
+
+	if ok, err := w.Work(ctx); !ok || err != nil {
+		t.Fatalf("esperado job executado novamente (%v)", err)
+	}
+	if executed != 1 || count(t, db, StatusDone) != 1 {
+		t.Fatal("esperado job concluído")
+	}
+}
+
+func TestRun(t *testing.T) {
+	db := connect(t)
+
+	for i := 0; i < 10; i++ {
+		if _, err := Enqueue(context.Background(), db, "emails", email{To: "a@a.com"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mutex sync.Mutex
+	done := make(chan struct{})
+	executed := 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := NewWorker(db, "emails", func(ctx context.Context, job *Job) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		executed++
+		if executed == 10 {
+			close(done)
+		}
+		return nil
+	}, Options{Workers: 3, PollInterval: time.Millisecond, OnError: func(job *Job, err error) {
+		t.Error(err)
+	}})
+
+	result := make(chan error)
+	go func() {
+		result <- w.Run(ctx)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("jobs não executados")
+	}
+
+	// o cancelamento encerra os workers
+	cancel()
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Fatalf("esperado %v obtido %v", context.Canceled, err)
+	}
+	if executed != 10 || count(t, db, StatusDone) != 10 {
+		t.Fatalf("esperado 10 jobs concluídos obtido %d", executed)
+	}
+}
@@ -1,8 +1,10 @@
 package rdd
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"reflect"
 	"sync"
 
 	"github.com/dopsilva/rdd/builder"
@@ -21,7 +23,7 @@ var (
 var onlyOnce sync.Once
 
 // Connect retorna a conexão com o banco de dados através da engine informada e da url de conexão.
-func Connect(de engine.Engine, url string) (Database, error) {
+func Connect(de engine.Engine, url string, options ...Option) (Database, error) {
 	var dbw Database
 	var db *sql.DB
 	var err error
@@ -53,23 +55,60 @@ func Connect(de engine.Engine, url string) (Database, error) {
 		}
 	}
 
-	dbw = &DatabaseWrapper{db: db, builder: builder.New(de)}
+	w := &DatabaseWrapper{db: db, engine: DatabaseEngine(de), builder: builder.New(de)}
+	for _, opt := range options {
+		opt(w)
+	}
+	if w.logger != nil {
+		// o log é o primeiro interceptor para registrar a chamada completa
+		w.interceptors = append([]Interceptor{logInterceptor(w.logger, w.slowThreshold)}, w.interceptors...)
+	}
+	if w.observer != nil && w.statsInterval > 0 {
+		w.done = make(chan struct{})
+		go w.reportPoolStats()
+	}
+	dbw = w
 
 	return dbw, nil
 }
 
 var (
-	registeredSchemas = make(map[string]*schema.Table)
+	// registeredSchemas são os schemas das entidades pelo tipo, pois entidades de pacotes
+	// diferentes podem ter o mesmo nome
+	registeredSchemas = make(map[reflect.Type]*schema.Table)
 )
 
 // Register registra o schema da entidade.
 func Register[T any]() {
 	e := Use[T]()
 	if v, ok := any(e).(Workarea[T]); ok {
-		registeredSchemas[v.Entity()] = v.Schema()
+		registeredSchemas[entityType[T]()] = v.Schema()
 	}
 }
 
+// relatedSchema retorna o schema da entidade do relacionamento. Com entidades de mesmo nome
+// em pacotes diferentes, é utilizada a entidade do pacote da entidade proprietária.
+func relatedSchema(owner *schema.Table, rel schema.Relation) (*schema.Table, bool) {
+	var pkg string
+	for t, s := range registeredSchemas {
+		if s == owner {
+			pkg = t.PkgPath()
+		}
+	}
+
+	var related *schema.Table
+	for t, s := range registeredSchemas {
+		if t.Name() != rel.Entity {
+			continue
+		}
+		if t.PkgPath() == pkg {
+			return s, true
+		}
+		related = s
+	}
+	return related, related != nil
+}
+
 func GetRegisteredSchemas() []*schema.Table {
 	ret := make([]*schema.Table, len(registeredSchemas))
 	i := 0
@@ -98,12 +137,15 @@ func (r Resultset[T]) Empty() bool {
 
 // Select executa a query no banco de dados retornando o resultset da entidade T.
 // O ideal nessa função é que seja executada uma query no padrão SQL-92.
-func Select[T any](db Database, q string, args ...any) (Resultset[T], error) {
+func Select[T any](db Database, q string, args ...any) (_ Resultset[T], err error) {
+	ctx, done := startOperation(context.Background(), db, entityName[T](), "select")
+	defer done(&err)
+
 	res := make(Resultset[T], 0)
 	empty := false
 
 	// executa a query
-	rows, err := db.Query(q, args...)
+	rows, err := queryContext(ctx, db, newCall[T](q, args))
 	if err != nil {
 		if !errors.Is(err, sql.ErrNoRows) {
 			return nil, err
@@ -113,6 +155,8 @@ func Select[T any](db Database, q string, args ...any) (Resultset[T], error) {
 	}
 
 	if !empty {
+		defer rows.Close()
+
 		// pega os nomes das colunas retornados
 		columns, err := rows.Columns()
 		if err != nil {
@@ -127,14 +171,33 @@ func Select[T any](db Database, q string, args ...any) (Resultset[T], error) {
 			// pega o endereço dos campos do resultset
 			fields := w.GetFieldsAddr(columns)
 
-			if len(fields) > 0 {
-				// lê as colunas do resultset
-				if err := rows.Scan(fields...); err != nil {
-					return nil, err
-				}
-				// armazena a entidade para retorno
-				res = append(res, e)
+			// as colunas sem campo desalinham o scan
+			if len(fields) != len(columns) {
+				err := w.instance().unmatchedColumns(columns)
+				w.Close()
+				res.Close()
+				return nil, err
+			}
+
+			// lê as colunas do resultset
+			if err := rows.Scan(fields...); err != nil {
+				w.Close()
+				res.Close()
+				return nil, err
 			}
+			w.instance().persisted = true
+			// com o identity map, retorna a instância já carregada na transação
+			if x := identify(db, e); x != e {
+				w.Close()
+				e = x
+			}
+			// armazena a entidade para retorno
+			res = append(res, e)
+		}
+
+		if err := rows.Err(); err != nil {
+			res.Close()
+			return nil, err
 		}
 	}
 
@@ -0,0 +1,419 @@
+package rdd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dopsilva/rdd/builder"
+	"github.com/dopsilva/rdd/schema"
+)
+
+// relationField é implementada pelos campos de relacionamento (BelongsTo, HasMany e ManyToMany)
+type relationField interface {
+	relationKind() schema.RelationKind
+	relationEntity() string
+	include(db Database, rel schema.Relation, owners []relationOwner) error
+	clear()
+
+	// cascadeSave grava as entidades relacionadas, retornando a chave da entidade do BelongsTo
+	cascadeSave(ctx context.Context, db Database, rel schema.Relation, key any) (any, error)
+	// cascadeRemove remove as entidades relacionadas à chave da entidade proprietária
+	cascadeRemove(ctx context.Context, db Database, rel schema.Relation, key any) error
+}
+
+// relationOwner é a entidade que recebe as entidades relacionadas
+type relationOwner struct {
+	key   any
+	field relationField
+}
+
+// BelongsTo é o relacionamento com a entidade referenciada por uma coluna da própria tabela.
+// Ex.: Autor rdd.BelongsTo[Usuario] `rdd-belongs-to:"autor_id"`
+//
+// As entidades relacionadas não retornam ao pool no Close da entidade.
+type BelongsTo[T any] struct {
+	value  *T
+	loaded bool
+}
+
+// Get retorna a entidade relacionada ou nil se não existir ou não foi carregada
+func (r *BelongsTo[T]) Get() *T {
+	return r.value
+}
+
+// Set define a entidade relacionada
+func (r *BelongsTo[T]) Set(value *T) {
+	r.value, r.loaded = value, true
+}
+
+// Loaded verifica se o relacionamento foi carregado pelo Include
+func (r *BelongsTo[T]) Loaded() bool {
+	return r.loaded
+}
+
+func (r *BelongsTo[T]) relationKind() schema.RelationKind { return schema.BelongsTo }
+func (r *BelongsTo[T]) relationEntity() string            { return entityName[T]() }
+
+func (r *BelongsTo[T]) clear() {
+	r.value, r.loaded = nil, false
+}
+
+func (r *BelongsTo[T]) include(db Database, rel schema.Relation, owners []relationOwner) error {
+	column := rel.References
+	if column == "" {
+		pk, ok := schemaOf[T]().PrimaryKey()
+		if !ok {
+			return fmt.Errorf("rdd: relation %s: %s has no single primary key", rel.Name, rel.Entity)
+		}
+		column = pk
+	}
+
+	res, err := selectRelated[T](db, rel, column, ownerKeys(owners))
+	if err != nil {
+		return err
+	}
+
+	related := make(map[any]*T, len(res))
+	for _, e := range res {
+		related[relationKeyOf(e, column)] = e
+	}
+
+	for _, o := range owners {
+		f := o.field.(*BelongsTo[T])
+		f.value, f.loaded = related[o.key], true
+	}
+
+	return nil
+}
+
+func (r *BelongsTo[T]) cascadeSave(ctx context.Context, db Database, rel schema.Relation, key any) (any, error) {
+	if r.value == nil {
+		return nil, nil
+	}
+	if err := saveEntity(ctx, db, r.value); err != nil {
+		return nil, err
+	}
+
+	column := rel.References
+	if column == "" {
+		pk, ok := schemaOf[T]().PrimaryKey()
+		if !ok {
+			return nil, fmt.Errorf("rdd: relation %s: %s has no single primary key", rel.Name, rel.Entity)
+		}
+		column = pk
+	}
+	return relationKeyOf(r.value, column), nil
+}
+
+func (r *BelongsTo[T]) cascadeRemove(ctx context.Context, db Database, rel schema.Relation, key any) error {
+	return nil
+}
+
+// HasMany é o relacionamento com as entidades que referenciam a própria tabela.
+// Ex.: Comentarios rdd.HasMany[Comentario] `rdd-has-many:"post_id"`
+//
+// As entidades relacionadas não retornam ao pool no Close da entidade.
+type HasMany[T any] struct {
+	values Resultset[T]
+	loaded bool
+}
+
+// Get retorna as entidades relacionadas
+func (r *HasMany[T]) Get() Resultset[T] {
+	return r.values
+}
+
+// Set define as entidades relacionadas
+func (r *HasMany[T]) Set(values ...*T) {
+	r.values, r.loaded = values, true
+}
+
+// Loaded verifica se o relacionamento foi carregado pelo Include
+func (r *HasMany[T]) Loaded() bool {
+	return r.loaded
+}
+
+func (r *HasMany[T]) relationKind() schema.RelationKind { return schema.HasMany }
+func (r *HasMany[T]) relationEntity() string            { return entityName[T]() }
+
+func (r *HasMany[T]) clear() {
+	r.values, r.loaded = nil, false
+}
+
+func (r *HasMany[T]) include(db Database, rel schema.Relation, owners []relationOwner) error {
+	res, err := selectRelated[T](db, rel, rel.Column, ownerKeys(owners))
+	if err != nil {
+		return err
+	}
+
+	related := make(map[any]Resultset[T])
+	for _, e := range res {
+		k := relationKeyOf(e, rel.Column)
+		related[k] = append(related[k], e)
+	}
+
+	for _, o := range owners {
+		f := o.field.(*HasMany[T])
+		f.values, f.loaded = related[o.key], true
+		if f.values == nil {
+			f.values = make(Resultset[T], 0)
+		}
+	}
+
+	return nil
+}
+
+func (r *HasMany[T]) cascadeSave(ctx context.Context, db Database, rel schema.Relation, key any) (any, error) {
+	for _, e := range r.values {
+		if err := assignKey(e, rel.Column, key); err != nil {
+			return nil, err
+		}
+		if err := saveEntity(ctx, db, e); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *HasMany[T]) cascadeRemove(ctx context.Context, db Database, rel schema.Relation, key any) error {
+	where := make([]builder.Condition, 0, 1)
+	if rel.Cascades(schema.CascadeSoftDelete) {
+		// os registros já removidos logicamente não são marcados novamente
+		if cond, ok := notRemovedCondition(schemaOf[T]()); ok {
+			where = append(where, cond)
+		}
+	}
+
+	res, err := selectRelated[T](db, rel, rel.Column, []any{key}, where...)
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+
+	for _, e := range res {
+		if err := removeEntity(ctx, db, e, rel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Include carrega os relacionamentos informados de todas as entidades do resultset.
+// É executada uma consulta por relacionamento para cada lote de até 500 chaves.
+func (r Resultset[T]) Include(db Database, names ...string) error {
+	if r.Empty() {
+		return nil
+	}
+
+	table := any(r[0]).(Workarea[T]).Schema()
+
+	for _, name := range names {
+		rel, ok := table.Relation(name)
+		if !ok {
+			return fmt.Errorf("rdd: relation %s not defined on %s", name, entityName[T]())
+		}
+
+		// coluna da própria tabela que identifica as entidades relacionadas
+		column := rel.Column
+		if rel.Kind != schema.BelongsTo {
+			var err error
+			if column, err = ownerColumn(table, rel); err != nil {
+				return err
+			}
+		}
+
+		owners := make([]relationOwner, 0, len(r))
+		for _, e := range r {
+			w := any(e).(Workarea[T]).instance()
+			owners = append(owners, relationOwner{key: relationKeyOf(e, column), field: w.relations[name]})
+		}
+
+		if err := owners[0].field.include(db, rel, owners); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ownerColumn retorna a coluna da própria tabela referenciada pelo HasMany e ManyToMany
+func ownerColumn(table *schema.Table, rel schema.Relation) (string, error) {
+	if rel.References != "" {
+		return rel.References, nil
+	}
+	pk, ok := table.PrimaryKey()
+	if !ok {
+		return "", fmt.Errorf("rdd: relation %s: %s has no single primary key", rel.Name, table.Name)
+	}
+	return pk, nil
+}
+
+// ownerKeys retorna as chaves distintas dos proprietários
+func ownerKeys(owners []relationOwner) []any {
+	keys := make([]any, 0, len(owners))
+	seen := make(map[any]bool, len(owners))
+	for _, o := range owners {
+		if o.key != nil && !seen[o.key] {
+			seen[o.key] = true
+			keys = append(keys, o.key)
+		}
+	}
+	return keys
+}
+
+// relationBatchSize é a quantidade máxima de chaves no in das consultas dos relacionamentos,
+// abaixo do limite de parâmetros dos bancos de dados
+var relationBatchSize = 500
+
+// keyBatches divide as chaves em lotes de até relationBatchSize chaves
+func keyBatches(keys []any) [][]any {
+	batches := make([][]any, 0, len(keys)/relationBatchSize+1)
+	for len(keys) > relationBatchSize {
+		batches = append(batches, keys[:relationBatchSize])
+		keys = keys[relationBatchSize:]
+	}
+	if len(keys) > 0 {
+		batches = append(batches, keys)
+	}
+	return batches
+}
+
+// selectRelated seleciona as entidades relacionadas através das chaves informadas e das
+// condições adicionais, com uma consulta para cada lote de chaves
+func selectRelated[T any](db Database, rel schema.Relation, column string, keys []any, where ...builder.Condition) (Resultset[T], error) {
+	table := schemaOf[T]()
+	if _, ok := table.Fields[column]; !ok {
+		return nil, fmt.Errorf("rdd: relation %s: column %s not defined on %s", rel.Name, column, rel.Entity)
+	}
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	res := make(Resultset[T], 0, len(keys))
+	for _, batch := range keyBatches(keys) {
+		q, args, err := db.Builder().Select(*table, &builder.SelectOptions{
+			Where: append([]builder.Condition{{Column: column, Operator: "in", Values: batch}}, where...),
+		})
+		if err != nil {
+			res.Close()
+			return nil, err
+		}
+
+		r, err := Select[T](db, q, args...)
+		if err != nil {
+			res.Close()
+			return nil, err
+		}
+		res = append(res, r...)
+	}
+
+	return res, nil
+}
+
+// relationKeyOf retorna o valor da coluna da entidade no formato usado como chave dos relacionamentos
+func relationKeyOf[T any](e *T, column string) any {
+	fi, ok := any(e).(Workarea[T]).instance().fields[column]
+	if !ok {
+		return nil
+	}
+
+	v, err := fieldValue(fi)
+	if err != nil {
+		return nil
+	}
+
+	return relationKey(v)
+}
+
+// relationKey normaliza o valor do driver para ser usado como chave dos relacionamentos
+func relationKey(v any) any {
+	switch t := v.(type) {
+	case []byte:
+		return string(t)
+	case time.Time:
+		return t.UnixNano()
+	}
+	return v
+}
+
+// parseRelationTags lê o relacionamento das tags do campo
+func parseRelationTags(owner, name string, tag reflect.StructTag, f relationField) schema.Relation {
+	rel := schema.Relation{Name: name, Kind: f.relationKind(), Entity: f.relationEntity()}
+
+	var key string
+	switch rel.Kind {
+	case schema.BelongsTo:
+		key = "rdd-belongs-to"
+	case schema.HasMany:
+		key = "rdd-has-many"
+	case schema.ManyToMany:
+		rel = parseJoinTags(owner, name, tag, rel)
+	}
+
+	if key != "" {
+		tv, ok := tag.Lookup(key)
+		if !ok || strings.TrimSpace(tv) == "" {
+			panic(fmt.Errorf("workarea: %s not defined on relation %s", key, name))
+		}
+		rel.Column = tv
+
+		if tv, ok := tag.Lookup("rdd-references"); ok {
+			rel.References = tv
+		}
+	}
+
+	if tv, ok := tag.Lookup("rdd-cascade"); ok {
+		rel.Cascade = parseCascadeTag(rel, tv)
+	}
+
+	return rel
+}
+
+// parseCascadeTag lê as operações em cascata aceitas pelo tipo de relacionamento.
+// BelongsTo aceita save, HasMany aceita remove ou soft-delete e save e ManyToMany aceita remove.
+func parseCascadeTag(rel schema.Relation, tv string) []string {
+	allowed := map[schema.RelationKind][]string{
+		schema.BelongsTo:  {schema.CascadeSave},
+		schema.HasMany:    {schema.CascadeRemove, schema.CascadeSoftDelete, schema.CascadeSave},
+		schema.ManyToMany: {schema.CascadeRemove},
+	}
+
+	cascade := make([]string, 0)
+	for _, v := range strings.Split(tv, ",") {
+		v = strings.TrimSpace(v)
+		if !slices.Contains(allowed[rel.Kind], v) {
+			panic(fmt.Errorf("workarea: invalid rdd-cascade %q on relation %s", v, rel.Name))
+		}
+		cascade = append(cascade, v)
+	}
+
+	if slices.Contains(cascade, schema.CascadeRemove) && slices.Contains(cascade, schema.CascadeSoftDelete) {
+		panic(fmt.Errorf("workarea: rdd-cascade remove and soft-delete are exclusive on relation %s", rel.Name))
+	}
+
+	return cascade
+}
+
+func entityName[T any]() string {
+	return entityType[T]().Name()
+}
+
+// entityType retorna o tipo da entidade, que a identifica entre os pacotes
+func entityType[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// schemaOf retorna o schema da entidade
+func schemaOf[T any]() *schema.Table {
+	if s, ok := registeredSchemas[entityType[T]()]; ok {
+		return s
+	}
+	e := Use[T]()
+	defer any(e).(Workarea[T]).Close()
+	return any(e).(Workarea[T]).Schema()
+}
@@ -0,0 +1,130 @@
+package rdd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dopsilva/rdd/engine"
+	"github.com/dopsilva/rdd/field"
+	"github.com/dopsilva/rdd/schema"
+)
+
+type Post struct {
+	Workarea[Post] `rdd-table:"posts"`
+
+	ID      field.Field[int64]  `rdd-column:"id" rdd-primary-key:"true"`
+	Titulo  field.Field[string] `rdd-column:"titulo"`
+	AutorID field.Field[string] `rdd-column:"autor_id"`
+
+	Autor       BelongsTo[Usuario]  `rdd-belongs-to:"autor_id"`
+	Comentarios HasMany[Comentario] `rdd-has-many:"post_id"`
+
+	ConstraintAutor field.Constraint `rdd-foreign-key:"autor_id" rdd-foreign-key-reference:"usuarios"`
+}
+
+type Comentario struct {
+	Workarea[Comentario] `rdd-table:"comentarios"`
+
+	ID     field.Field[int64]  `rdd-column:"id" rdd-primary-key:"true"`
+	PostID field.Field[int64]  `rdd-column:"post_id"`
+	Texto  field.Field[string] `rdd-column:"texto"`
+
+	Post BelongsTo[Post] `rdd-belongs-to:"post_id" rdd-references:"id"`
+}
+
+func init() {
+	Register[Post]()
+	Register[Comentario]()
+}
+
+func TestInclude(t *testing.T) {
+	queries := 0
+	counter := func(ctx context.Context, call *Call, next Handler) (*CallResult, error) {
+		if call.Kind == QueryCall {
+			queries++
+		}
+		return next(ctx, call)
+	}
+
+	db, err := Connect(engine.SQLite, ":memory:", WithInterceptors(counter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, e := range []*schema.Table{schemaOf[Usuario](), schemaOf[Post](), schemaOf[Comentario]()} {
+		if err := db.CreateTable(e, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, q := range []string{
+		"insert into usuarios (id, email, nome, incluido_em) values ('u1', 'a@a.com', 'Ana', '2024-01-01'), ('u2', 'b@b.com', 'Bia', '2024-01-01')",
+		"insert into posts (id, titulo, autor_id) values (1, 'um', 'u1'), (2, 'dois', 'u1'), (3, 'três', 'u2')",
+		"insert into comentarios (id, post_id, texto) values (1, 1, 'a'), (2, 1, 'b'), (3, 2, 'c')",
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	posts, err := Select[Post](db, "select id, titulo, autor_id from posts order by id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer posts.Close()
+
+	if posts[0].Autor.Loaded() {
+		t.Fatal("não esperado relacionamento carregado")
+	}
+
+	queries = 0
+	if err := posts.Include(db, "Autor", "Comentarios"); err != nil {
+		t.Fatal(err)
+	}
+	if queries != 2 {
+		t.Fatalf("esperado 2 consultas obtido %d", queries)
+	}
+
+	if posts[0].Autor.Get() == nil || posts[0].Autor.Get() != posts[1].Autor.Get() || posts[2].Autor.Get().Nome.Get() != "Bia" {
+		t.Fatal("autores inesperados")
+	}
+	if posts[0].Comentarios.Get().Len() != 2 || posts[1].Comentarios.Get().Len() != 1 {
+		t.Fatal("comentários inesperados")
+	}
+	if !posts[2].Comentarios.Loaded() || !posts[2].Comentarios.Get().Empty() {
+		t.Fatal("esperado relacionamento carregado sem comentários")
+	}
+
+	// as chaves são divididas em lotes
+	defer func(n int) { relationBatchSize = n }(relationBatchSize)
+	relationBatchSize = 2
+
+	queries = 0
+	if err := posts.Include(db, "Autor", "Comentarios"); err != nil {
+		t.Fatal(err)
+	}
+	if queries != 3 {
+		t.Fatalf("esperado 3 consultas obtido %d", queries)
+	}
+	if posts[2].Autor.Get().Nome.Get() != "Bia" || posts[0].Comentarios.Get().Len() != 2 || posts[1].Comentarios.Get().Len() != 1 {
+		t.Fatal("relacionamentos inesperados com lotes")
+	}
+
+	comentarios := posts[0].Comentarios.Get()
+	if err := comentarios.Include(db, "Post"); err != nil {
+		t.Fatal(err)
+	}
+	if comentarios[0].Post.Get().Titulo.Get() != "um" {
+		t.Fatal("post inesperado")
+	}
+
+	if err := posts.Include(db, "Tags"); err == nil {
+		t.Fatal("esperado erro de relacionamento inexistente")
+	}
+
+	posts[0].Reset()
+	if posts[0].Autor.Loaded() || posts[0].Comentarios.Get() != nil {
+		t.Fatal("esperado relacionamento limpo após o reset")
+	}
+}
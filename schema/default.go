@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Funções portáveis aceitas no rdd-default. Cada builder converte a função para o seu dialeto.
+const (
+	DefaultNewUUID     = "new_uuid"
+	DefaultNow         = "now"
+	DefaultCurrentDate = "current_date"
+	DefaultCurrentTime = "current_time"
+)
+
+// DefaultFunctions são as funções portáveis aceitas no rdd-default
+var DefaultFunctions = []string{DefaultNewUUID, DefaultNow, DefaultCurrentDate, DefaultCurrentTime}
+
+// DefaultValue é o valor padrão da coluna interpretado a partir do rdd-default
+type DefaultValue struct {
+	// Function é o nome da função portável; vazio quando o valor é literal
+	Function string
+	// Literal é o valor literal: nil, string, int64, float64 ou bool
+	Literal any
+}
+
+// IsFunction verifica se o valor padrão é uma função portável
+func (d DefaultValue) IsFunction() bool {
+	return d.Function != ""
+}
+
+// ParseDefault interpreta o rdd-default. São aceitos literais numéricos, textos entre
+// aspas simples ('pending'), true, false, null e as funções portáveis (DefaultFunctions).
+func ParseDefault(spec string) (DefaultValue, error) {
+	s := strings.TrimSpace(spec)
+
+	switch strings.ToLower(s) {
+	case "":
+		return DefaultValue{}, fmt.Errorf("schema: empty default")
+	case "null":
+		return DefaultValue{}, nil
+	case "true":
+		return DefaultValue{Literal: true}, nil
+	case "false":
+		return DefaultValue{Literal: false}, nil
+	}
+
+	for _, f := range DefaultFunctions {
+		if s == f {
+			return DefaultValue{Function: f}, nil
+		}
+	}
+
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		inner := s[1 : len(s)-1]
+		if strings.Contains(strings.ReplaceAll(inner, "''", ""), "'") {
+			return DefaultValue{}, fmt.Errorf("schema: invalid default %q: unescaped quote", spec)
+		}
+		return DefaultValue{Literal: strings.ReplaceAll(inner, "''", "'")}, nil
+	}
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return DefaultValue{Literal: n}, nil
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return DefaultValue{Literal: n}, nil
+	}
+
+	return DefaultValue{}, fmt.Errorf("schema: invalid default %q: expected a literal or one of [%s]", spec, strings.Join(DefaultFunctions, ", "))
+}
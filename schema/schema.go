@@ -1,9 +1,13 @@
 package schema
 
+import "github.com/dopsilva/rdd/engine"
+
 type Table struct {
 	Name        string
 	Fields      map[string]Field
 	ForeignKeys []ForeignKey
+	Checks      []Check
+	Relations   []Relation
 }
 
 type Field struct {
@@ -13,10 +17,112 @@ type Field struct {
 	AutoGenerated bool
 	Nullable      bool
 	Default       string
+	Defaults      map[engine.Engine]string // expressões do padrão por engine (rdd-default-<engine>)
 	FieldType     string
+	Sensitive     bool
+	Check         string // expressão da restrição da coluna
+	Generated     string // expressão da coluna gerada (generated always as ... stored)
+	SoftDelete    bool   // coluna que marca o registro como removido
+
+	// regras de validação
+	Required  bool
+	MaxLength int
+	Min       *float64
+	Max       *float64
+	Pattern   string
+	Enum      []string
+}
+
+// Check é a restrição da tabela
+type Check struct {
+	Name       string
+	Expression string
+}
+
+// Computed verifica se o valor do campo é gerado pelo banco de dados
+func (f Field) Computed() bool {
+	return f.AutoGenerated || f.Generated != ""
 }
 
 type ForeignKey struct {
 	Fields    []string
 	Reference string
 }
+
+type RelationKind int
+
+const (
+	BelongsTo RelationKind = iota + 1
+	HasMany
+	ManyToMany
+)
+
+// Relation é o relacionamento da entidade com outra entidade.
+// No BelongsTo, Column é a coluna da própria tabela e References a coluna da entidade relacionada.
+// No HasMany, Column é a coluna da entidade relacionada e References a coluna da própria tabela.
+// No ManyToMany, Column é a coluna da tabela de associação (JoinTable) que referencia a própria tabela
+// e JoinColumn a coluna que referencia a primary key da entidade relacionada.
+// Se References não for informado é utilizada a primary key.
+type Relation struct {
+	Name       string
+	Kind       RelationKind
+	Entity     string
+	Column     string
+	References string
+	JoinTable  string
+	JoinColumn string
+	Cascade    []string
+}
+
+// Operações executadas em cascata nos relacionamentos (rdd-cascade)
+const (
+	CascadeRemove     = "remove"
+	CascadeSoftDelete = "soft-delete"
+	CascadeSave       = "save"
+)
+
+// Cascades verifica se alguma das operações é executada em cascata no relacionamento
+func (r Relation) Cascades(actions ...string) bool {
+	for _, c := range r.Cascade {
+		for _, a := range actions {
+			if c == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Relation retorna o relacionamento pelo nome
+func (t *Table) Relation(name string) (Relation, bool) {
+	for _, r := range t.Relations {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return Relation{}, false
+}
+
+// SoftDeleteColumn retorna a coluna que marca o registro como removido
+func (t *Table) SoftDeleteColumn() (string, bool) {
+	for _, f := range t.Fields {
+		if f.SoftDelete {
+			return f.Name, true
+		}
+	}
+	return "", false
+}
+
+// PrimaryKey retorna a coluna da primary key. Retorna falso se a primary key não existir ou for composta.
+func (t *Table) PrimaryKey() (string, bool) {
+	pk := ""
+	for _, f := range t.Fields {
+		if f.PrimaryKey {
+			if pk != "" {
+				return "", false
+			}
+			pk = f.Name
+		}
+	}
+	return pk, pk != ""
+}
@@ -0,0 +1,126 @@
+package rdd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/dopsilva/rdd/field"
+	"github.com/google/uuid"
+)
+
+type Arquivo struct {
+	Workarea[Arquivo] `rdd-table:"arquivos"`
+
+	ID       field.Field[uuid.UUID]     `rdd-column:"id" rdd-primary-key:"true"`
+	Paginas  field.Field[int32]         `rdd-column:"paginas"`
+	Versao   field.Field[int16]         `rdd-column:"versao"`
+	Conteudo field.Field[[]byte]        `rdd-column:"conteudo"`
+	Valor    field.Field[field.Decimal] `rdd-column:"valor"`
+	Metadata field.Field[field.JSON]    `rdd-column:"metadata" rdd-nullable:"true"`
+}
+
+type Tarifa struct {
+	Workarea[Tarifa] `rdd-table:"tarifas"`
+
+	ID    field.Field[int64]         `rdd-column:"id" rdd-primary-key:"true"`
+	Valor field.Field[field.Decimal] `rdd-column:"valor" rdd-min:"0" rdd-max:"1000.5"`
+}
+
+func init() {
+	Register[Arquivo]()
+	Register[Tarifa]()
+}
+
+func TestFieldTypes(t *testing.T) {
+	defer truncateTable(testDatabase, "arquivos")
+
+	a := Use[Arquivo]()
+	defer a.Close()
+
+	id := uuid.New()
+	meta, err := field.NewJSON(map[string]string{"autor": "Daniel"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.ID.Set(id)
+	a.Paginas.Set(120)
+	a.Versao.Set(3)
+	a.Conteudo.Set([]byte{0, 1, 2, 3})
+	a.Valor.Set(field.MustParseDecimal("1234567890.12"))
+	a.Metadata.Set(meta)
+
+	if err := a.Append(testContext, testDatabase); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Select[Arquivo](testDatabase, "select id, paginas, versao, conteudo, valor, metadata from arquivos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Close()
+
+	if res.Len() != 1 {
+		t.Fatalf("esperado 1 obtido %d", res.Len())
+	}
+
+	r := res[0]
+	if r.ID.Get() != id || r.Paginas.Get() != 120 || r.Versao.Get() != 3 {
+		t.Fatalf("valores inesperados %v %d %d", r.ID.Get(), r.Paginas.Get(), r.Versao.Get())
+	}
+	if !bytes.Equal(r.Conteudo.Get(), []byte{0, 1, 2, 3}) {
+		t.Fatalf("conteúdo inesperado %v", r.Conteudo.Get())
+	}
+	if r.Valor.Get().String() != "1234567890.12" {
+		t.Fatalf("esperado 1234567890.12 obtido %s", r.Valor.Get())
+	}
+
+	var m map[string]string
+	if err := r.Metadata.Get().Unmarshal(&m); err != nil || m["autor"] != "Daniel" {
+		t.Fatalf("metadata inesperado %v (%v)", m, err)
+	}
+}
+
+func TestDecimalRules(t *testing.T) {
+	defer truncateTable(testDatabase, "tarifas")
+
+	tr := Use[Tarifa]()
+	defer tr.Close()
+
+	// rdd-min e rdd-max comparam os decimais sem perda de precisão
+	var tests = []struct {
+		value string
+		rule  string
+	}{
+		{"-0.01", RuleMin},
+		{"1000.51", RuleMax},
+		{"1000.50", ""},
+	}
+
+	for _, test := range tests {
+		tr.ID.Set(1)
+		tr.Valor.Set(field.MustParseDecimal(test.value))
+
+		err := tr.Append(testContext, testDatabase)
+
+		var verrs ValidationErrors
+		if test.rule == "" {
+			if err != nil {
+				t.Fatalf("%s: %v", test.value, err)
+			}
+			continue
+		}
+		if !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Rule != test.rule {
+			t.Fatalf("%s: esperado a regra %s obtido %v", test.value, test.rule, err)
+		}
+	}
+
+	// no sqlite os decimais são armazenados como texto
+	if _, err := Sum[Tarifa, string](testDatabase, "valor"); err == nil {
+		t.Fatal("esperado erro com a soma da coluna decimal")
+	}
+	if _, err := Paginate[Tarifa](testDatabase, PageRequest{OrderBy: []string{"valor"}}); err == nil {
+		t.Fatal("esperado erro com a paginação por keyset na coluna decimal")
+	}
+}
@@ -0,0 +1,275 @@
+package rdd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/dopsilva/rdd/field"
+	"github.com/dopsilva/rdd/schema"
+)
+
+// Persistable é implementada pelas entidades (Workarea) gravadas pela UnitOfWork
+type Persistable interface {
+	Schema() *schema.Table
+	Entity() string
+
+	Append(ctx context.Context, db Database) error
+	Replace(ctx context.Context, db Database) error
+	Remove(ctx context.Context, db Database) error
+
+	GetFieldsAddr(columns []string) []any
+}
+
+type unitEntry struct {
+	entity Persistable
+	op     Operation
+}
+
+type unitRelation struct {
+	child  Persistable
+	parent Persistable
+	column string
+}
+
+// UnitOfWork acumula as operações de entidades de tipos diferentes e as executa em uma
+// única transação. As operações são agrupadas por tabela e ordenadas pelas foreign keys
+// e relacionamentos BelongsTo dos schemas: Append e Replace da tabela referenciada para a
+// tabela que a referencia e Remove na ordem inversa. Tabelas com dependência cíclica
+// mantêm a ordem de registro. Os comandos não são executados em lote: cada entidade
+// executa o seu próprio comando, com as validações e eventos da workarea.
+type UnitOfWork struct {
+	entries   []unitEntry
+	relations []unitRelation
+}
+
+// NewUnitOfWork cria a unidade de trabalho
+func NewUnitOfWork() *UnitOfWork {
+	return &UnitOfWork{}
+}
+
+// Append registra as entidades para inclusão
+func (u *UnitOfWork) Append(entities ...Persistable) {
+	u.register(Append, entities)
+}
+
+// Replace registra as entidades para alteração
+func (u *UnitOfWork) Replace(entities ...Persistable) {
+	u.register(Replace, entities)
+}
+
+// Remove registra as entidades para remoção
+func (u *UnitOfWork) Remove(entities ...Persistable) {
+	u.register(Delete, entities)
+}
+
+// Relate define a coluna da entidade filha com a primary key da entidade pai antes da
+// gravação da filha, permitindo relacionar entidades com chaves geradas pelo banco de dados.
+// Se a coluna não for informada é utilizada a foreign key da filha que referencia a tabela do pai.
+func (u *UnitOfWork) Relate(child, parent Persistable, column ...string) {
+	r := unitRelation{child: child, parent: parent}
+	if len(column) > 0 {
+		r.column = column[0]
+	}
+	u.relations = append(u.relations, r)
+}
+
+// Len retorna a quantidade de operações pendentes
+func (u *UnitOfWork) Len() int {
+	return len(u.entries)
+}
+
+// Commit executa as operações pendentes em uma transação (ou savepoint, se o banco de
+// dados já estiver em uma transação). Após a confirmação as operações são descartadas.
+func (u *UnitOfWork) Commit(ctx context.Context, db Database) error {
+	if len(u.entries) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := u.flush(ctx, tx); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	u.entries, u.relations = nil, nil
+
+	return nil
+}
+
+func (u *UnitOfWork) register(op Operation, entities []Persistable) {
+	for _, e := range entities {
+		if !slices.Contains(u.entries, unitEntry{entity: e, op: op}) {
+			u.entries = append(u.entries, unitEntry{entity: e, op: op})
+		}
+	}
+}
+
+// flush executa as operações agrupadas por tabela na ordem das dependências, um comando
+// por entidade
+func (u *UnitOfWork) flush(ctx context.Context, db Database) error {
+	order := u.order()
+
+	for _, op := range []Operation{Append, Replace, Delete} {
+		tables := order
+		if op == Delete {
+			tables = slices.Clone(order)
+			slices.Reverse(tables)
+		}
+
+		for _, table := range tables {
+			for _, e := range u.entries {
+				if e.op != op || e.entity.Schema().Name != table {
+					continue
+				}
+				if err := u.execute(ctx, db, e); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (u *UnitOfWork) execute(ctx context.Context, db Database, e unitEntry) error {
+	switch e.op {
+	case Append, Replace:
+		if err := u.relate(e.entity); err != nil {
+			return err
+		}
+		if e.op == Append {
+			return e.entity.Append(ctx, db)
+		}
+		return e.entity.Replace(ctx, db)
+	case Delete:
+		return e.entity.Remove(ctx, db)
+	}
+	return nil
+}
+
+// relate define as colunas da entidade com as chaves das entidades relacionadas
+func (u *UnitOfWork) relate(child Persistable) error {
+	for _, r := range u.relations {
+		if r.child != child {
+			continue
+		}
+
+		column := r.column
+		if column == "" {
+			for _, fk := range child.Schema().ForeignKeys {
+				if fk.Reference == r.parent.Schema().Name && len(fk.Fields) == 1 {
+					if column != "" {
+						return fmt.Errorf("rdd: %s has more than one foreign key to %s", child.Entity(), r.parent.Entity())
+					}
+					column = fk.Fields[0]
+				}
+			}
+			if column == "" {
+				return fmt.Errorf("rdd: %s has no foreign key to %s", child.Entity(), r.parent.Entity())
+			}
+		}
+
+		pk, ok := r.parent.Schema().PrimaryKey()
+		if !ok {
+			return fmt.Errorf("rdd: %s has no single primary key", r.parent.Entity())
+		}
+
+		src := r.parent.GetFieldsAddr([]string{pk})
+		dst := child.GetFieldsAddr([]string{column})
+		if len(src) == 0 || len(dst) == 0 {
+			return fmt.Errorf("rdd: column %s not defined on %s", column, child.Entity())
+		}
+
+		value, err := fieldValue(field.FieldInstance{Addr: src[0]})
+		if err != nil {
+			return err
+		}
+		f, ok := dst[0].(field.Assignable)
+		if !ok {
+			return fmt.Errorf("workarea: field %s does not accept %T", column, value)
+		}
+		if err := f.Assign(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// order retorna as tabelas das operações ordenadas pelas dependências, da tabela
+// referenciada para a tabela que a referencia
+func (u *UnitOfWork) order() []string {
+	tables := make([]string, 0)
+	schemas := make(map[string]*schema.Table)
+	for _, e := range u.entries {
+		s := e.entity.Schema()
+		if _, ok := schemas[s.Name]; !ok {
+			schemas[s.Name] = s
+			tables = append(tables, s.Name)
+		}
+	}
+
+	// dependências entre as tabelas presentes, ignorando auto relacionamentos
+	deps := make(map[string][]string, len(tables))
+	for _, t := range tables {
+		references := make([]string, 0)
+		for _, fk := range schemas[t].ForeignKeys {
+			references = append(references, fk.Reference)
+		}
+		for _, rel := range schemas[t].Relations {
+			if s, ok := relatedSchema(schemas[t], rel); ok && rel.Kind == schema.BelongsTo {
+				references = append(references, s.Name)
+			}
+		}
+
+		for _, r := range references {
+			if r != t && schemas[r] != nil && !slices.Contains(deps[t], r) {
+				deps[t] = append(deps[t], r)
+			}
+		}
+	}
+
+	order := make([]string, 0, len(tables))
+	done := make(map[string]bool, len(tables))
+
+	for len(order) < len(tables) {
+		progress := false
+		for _, t := range tables {
+			if done[t] {
+				continue
+			}
+			ready := true
+			for _, d := range deps[t] {
+				if !done[d] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				order = append(order, t)
+				done[t] = true
+				progress = true
+			}
+		}
+
+		// dependência cíclica: mantém a ordem de registro
+		if !progress {
+			for _, t := range tables {
+				if !done[t] {
+					order = append(order, t)
+					done[t] = true
+				}
+			}
+		}
+	}
+
+	return order
+}
@@ -0,0 +1,112 @@
+package rdd
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dopsilva/rdd/engine"
+	"github.com/dopsilva/rdd/schema"
+)
+
+var comentariosConfirmados int
+
+func (c *Comentario) AfterCommit(params EventParameters) error {
+	comentariosConfirmados++
+	return nil
+}
+
+func TestUnitOfWork(t *testing.T) {
+	comentariosConfirmados = 0
+
+	tables := make([]string, 0)
+	recorder := func(ctx context.Context, call *Call, next Handler) (*CallResult, error) {
+		if call.Schema != nil && (call.Kind == ExecCall || call.Kind == QueryRowCall) {
+			tables = append(tables, strings.Fields(call.Query)[0]+" "+call.Schema.Name)
+		}
+		return next(ctx, call)
+	}
+
+	db, err := Connect(engine.SQLite, ":memory:", WithInterceptors(recorder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, e := range []*schema.Table{schemaOf[Usuario](), schemaOf[Post](), schemaOf[Comentario](), schemaOf[Cliente]()} {
+		if err := db.CreateTable(e, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	u, p, c := Use[Usuario](), Use[Post](), Use[Comentario]()
+	defer u.Close()
+	defer p.Close()
+	defer c.Close()
+
+	u.Email.Set("dopslv@gmail.com")
+	u.Nome.Set("Daniel")
+	p.ID.Set(1)
+	p.Titulo.Set("um")
+	c.ID.Set(1)
+	c.PostID.Set(1)
+	c.Texto.Set("a")
+
+	uow := NewUnitOfWork()
+	uow.Append(c, p, u)
+	uow.Append(c)
+	uow.Relate(p, u)
+
+	if uow.Len() != 3 {
+		t.Fatalf("esperado 3 operações obtido %d", uow.Len())
+	}
+
+	if err := uow.Commit(testContext, db); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(tables, []string{"insert usuarios", "insert posts", "insert comentarios"}) {
+		t.Fatalf("ordem inesperada %v", tables)
+	}
+	if u.ID.Get() == "" || p.AutorID.Get() != u.ID.Get() {
+		t.Fatal("esperado autor relacionado ao usuário")
+	}
+	if comentariosConfirmados != 1 || uow.Len() != 0 {
+		t.Fatal("esperado AfterCommit do comentário")
+	}
+
+	// remove da tabela que referencia para a tabela referenciada
+	tables = tables[:0]
+	uow.Remove(u, p, c)
+	if err := uow.Commit(testContext, db); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(tables, []string{"delete comentarios", "delete posts", "delete usuarios"}) {
+		t.Fatalf("ordem inesperada %v", tables)
+	}
+
+	// a falha desfaz todas as operações
+	n, cl := Use[Usuario](), Use[Cliente]()
+	defer n.Close()
+	defer cl.Close()
+
+	n.Email.Set("outro@gmail.com")
+	n.Nome.Set("Outro")
+	cl.Nome.Set("Nome muito comprido")
+
+	uow.Append(n, cl)
+
+	var verrs ValidationErrors
+	if err := uow.Commit(testContext, db); !errors.As(err, &verrs) {
+		t.Fatalf("esperado erro de validação obtido %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow("select count(*) from usuarios").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Fatal("esperado inclusão desfeita")
+	}
+}
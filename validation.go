@@ -0,0 +1,284 @@
+package rdd
+
+import (
+	"cmp"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dopsilva/rdd/field"
+	"github.com/dopsilva/rdd/schema"
+)
+
+// Regras de validação suportadas pelas tags
+const (
+	RuleRequired  = "required"
+	RuleMaxLength = "max-length"
+	RuleMin       = "min"
+	RuleMax       = "max"
+	RulePattern   = "pattern"
+	RuleEnum      = "enum"
+)
+
+// ValidationError descreve a falha de uma regra em uma coluna da entidade
+type ValidationError struct {
+	Column  string
+	Rule    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Column == "" {
+		return e.Message
+	}
+	return e.Column + ": " + e.Message
+}
+
+// ValidationErrors agrega todas as falhas de validação da entidade
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, v := range e {
+		msgs[i] = v.Error()
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Columns retorna as colunas que falharam na validação
+func (e ValidationErrors) Columns() []string {
+	columns := make([]string, 0, len(e))
+	for _, v := range e {
+		if v.Column != "" {
+			columns = append(columns, v.Column)
+		}
+	}
+	return columns
+}
+
+// Validatable é implementada pelas entidades que possuem regras de validação próprias.
+// O Validate é executado no Append e Replace após a validação das tags.
+type Validatable interface {
+	Validate(params EventParameters) error
+}
+
+var patterns sync.Map
+
+// parseValidationTags lê as regras de validação das tags do campo
+func parseValidationTags(tag reflect.StructTag, f *schema.Field) {
+	if tv, ok := tag.Lookup("rdd-required"); ok {
+		f.Required, _ = strconv.ParseBool(tv)
+	}
+	if tv, ok := tag.Lookup("rdd-max-length"); ok {
+		v, err := strconv.Atoi(tv)
+		if err != nil {
+			panic(fmt.Errorf("workarea: invalid rdd-max-length on %s: %w", f.Name, err))
+		}
+		f.MaxLength = v
+	}
+	if tv, ok := tag.Lookup("rdd-min"); ok {
+		v, err := strconv.ParseFloat(tv, 64)
+		if err != nil {
+			panic(fmt.Errorf("workarea: invalid rdd-min on %s: %w", f.Name, err))
+		}
+		f.Min = &v
+	}
+	if tv, ok := tag.Lookup("rdd-max"); ok {
+		v, err := strconv.ParseFloat(tv, 64)
+		if err != nil {
+			panic(fmt.Errorf("workarea: invalid rdd-max on %s: %w", f.Name, err))
+		}
+		f.Max = &v
+	}
+	if tv, ok := tag.Lookup("rdd-pattern"); ok {
+		re, err := regexp.Compile(tv)
+		if err != nil {
+			panic(fmt.Errorf("workarea: invalid rdd-pattern on %s: %w", f.Name, err))
+		}
+		patterns.Store(tv, re)
+		f.Pattern = tv
+	}
+	if tv, ok := tag.Lookup("rdd-enum"); ok {
+		for _, v := range strings.Split(tv, ",") {
+			f.Enum = append(f.Enum, strings.TrimSpace(v))
+		}
+	}
+}
+
+// validate executa as regras de validação dos campos e da entidade
+func (w *workarea[T]) validate(params EventParameters) error {
+	var errs ValidationErrors
+
+	columns := make([]string, 0, len(w.fields))
+	for k := range w.fields {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+
+	for _, c := range columns {
+		errs = append(errs, validateField(w.fields[c], params.Operation)...)
+	}
+
+	if v, ok := any(w.entity).(Validatable); ok {
+		if err := v.Validate(params); err != nil {
+			var verrs ValidationErrors
+			var verr ValidationError
+			if errors.As(err, &verrs) {
+				errs = append(errs, verrs...)
+			} else if errors.As(err, &verr) {
+				errs = append(errs, verr)
+			} else {
+				errs = append(errs, ValidationError{Message: err.Error()})
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// validateField valida o valor do campo de acordo com as regras do schema
+func validateField(fi field.FieldInstance, op Operation) []ValidationError {
+	s := fi.Schema
+	errs := make([]ValidationError, 0)
+
+	value, err := fieldValue(fi)
+	if err != nil {
+		return append(errs, ValidationError{Column: s.Name, Message: err.Error()})
+	}
+
+	if isEmptyValue(value) {
+		// campos gerados pelo banco de dados não precisam de valor
+		if s.Required && !s.Computed() {
+			errs = append(errs, ValidationError{Column: s.Name, Rule: RuleRequired, Message: "is required"})
+		} else if len(s.Enum) > 0 && !s.Nullable && !s.Computed() && !(op == Append && (s.Default != "" || len(s.Defaults) > 0)) {
+			// o valor vazio não atende à constraint dos valores enumerados
+			errs = append(errs, ValidationError{Column: s.Name, Rule: RuleEnum, Message: fmt.Sprintf("must be one of [%s]", strings.Join(s.Enum, ", "))})
+		}
+		return errs
+	}
+
+	if s.MaxLength > 0 {
+		var n int
+		switch v := value.(type) {
+		case string:
+			n = utf8.RuneCountInString(v)
+		case []byte:
+			n = len(v)
+		}
+		if n > s.MaxLength {
+			errs = append(errs, ValidationError{Column: s.Name, Rule: RuleMaxLength, Message: fmt.Sprintf("exceeds max length of %d", s.MaxLength)})
+		}
+	}
+
+	if s.Min != nil {
+		if c, ok := compareLimit(value, s.FieldType, *s.Min); ok && c < 0 {
+			errs = append(errs, ValidationError{Column: s.Name, Rule: RuleMin, Message: fmt.Sprintf("must be greater than or equal to %v", *s.Min)})
+		}
+	}
+	if s.Max != nil {
+		if c, ok := compareLimit(value, s.FieldType, *s.Max); ok && c > 0 {
+			errs = append(errs, ValidationError{Column: s.Name, Rule: RuleMax, Message: fmt.Sprintf("must be less than or equal to %v", *s.Max)})
+		}
+	}
+
+	if s.Pattern != "" {
+		if v, ok := value.(string); ok {
+			re, _ := patterns.Load(s.Pattern)
+			if re == nil {
+				re = regexp.MustCompile(s.Pattern)
+				patterns.Store(s.Pattern, re)
+			}
+			if !re.(*regexp.Regexp).MatchString(v) {
+				errs = append(errs, ValidationError{Column: s.Name, Rule: RulePattern, Message: fmt.Sprintf("must match pattern %q", s.Pattern)})
+			}
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		v := fmt.Sprint(value)
+		found := false
+		for _, e := range s.Enum {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, ValidationError{Column: s.Name, Rule: RuleEnum, Message: fmt.Sprintf("must be one of [%s]", strings.Join(s.Enum, ", "))})
+		}
+	}
+
+	return errs
+}
+
+// fieldValue obtém o valor do campo no formato do driver
+func fieldValue(fi field.FieldInstance) (any, error) {
+	v, ok := fi.Addr.(field.Valuer)
+	if !ok {
+		return nil, nil
+	}
+
+	value, err := v.Value()
+	if err != nil {
+		return nil, err
+	}
+
+	// tipos sql.Null* retornam a própria estrutura
+	if vv, ok := value.(driver.Valuer); ok {
+		return vv.Value()
+	}
+
+	return value, nil
+}
+
+func isEmptyValue(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case time.Time:
+		return t.IsZero()
+	}
+	return false
+}
+
+// compareLimit compara o valor do campo com o limite do rdd-min ou rdd-max, retornando
+// -1, 0 ou 1. Os decimais são enviados ao driver como texto e comparados sem perda de precisão.
+func compareLimit(v any, fieldType string, limit float64) (int, bool) {
+	if s, ok := v.(string); ok && fieldType == "Decimal" {
+		d, err := field.ParseDecimal(s)
+		if err != nil {
+			return 0, false
+		}
+		return d.Cmp(field.MustParseDecimal(strconv.FormatFloat(limit, 'f', -1, 64))), true
+	}
+
+	n, ok := numericValue(v)
+	if !ok {
+		return 0, false
+	}
+	return cmp.Compare(n, limit), true
+}
+
+func numericValue(v any) (float64, bool) {
+	switch t := v.(type) {
+	case int64:
+		return float64(t), true
+	case float64:
+		return t, true
+	}
+	return 0, false
+}
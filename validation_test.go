@@ -0,0 +1,86 @@
+package rdd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dopsilva/rdd/field"
+)
+
+type Cliente struct {
+	Workarea[Cliente] `rdd-table:"clientes"`
+
+	ID       field.Field[string] `rdd-column:"id" rdd-primary-key:"true" rdd-auto-generated:"true" rdd-default:"new_uuid"`
+	Nome     field.Field[string] `rdd-column:"nome" rdd-required:"true" rdd-max-length:"10"`
+	Idade    field.Field[int64]  `rdd-column:"idade" rdd-min:"0" rdd-max:"150"`
+	Email    field.Field[string] `rdd-column:"email" rdd-pattern:"^[^@]+@[^@]+$"`
+	Situacao field.Field[string] `rdd-column:"situacao" rdd-enum:"ativo,bloqueado" rdd-default:"'ativo'"`
+}
+
+func (c *Cliente) Validate(params EventParameters) error {
+	if c.Situacao.Get() == "bloqueado" && c.Idade.Get() < 18 {
+		return ValidationError{Column: "situacao", Message: "menores não podem ser bloqueados"}
+	}
+	return nil
+}
+
+func init() {
+	Register[Cliente]()
+}
+
+func TestValidation(t *testing.T) {
+	defer truncateTable(testDatabase, "clientes")
+
+	c := Use[Cliente]()
+	defer c.Close()
+
+	c.Nome.Set("Nome muito comprido")
+	c.Idade.Set(-1)
+	c.Email.Set("email inválido")
+	c.Situacao.Set("removido")
+
+	err := c.Append(testContext, testDatabase)
+
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("esperado ValidationErrors obtido %v", err)
+	}
+
+	expected := []string{"email", "idade", "nome", "situacao"}
+	if !reflect.DeepEqual(verrs.Columns(), expected) {
+		t.Fatalf("esperado %v obtido %v", expected, verrs.Columns())
+	}
+
+	c.Reset()
+	c.Nome.Set("Daniel")
+	c.Idade.Set(10)
+	c.Situacao.Set("bloqueado")
+
+	err = c.Replace(testContext, testDatabase)
+	if !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Column != "situacao" {
+		t.Fatalf("esperado erro de validação da entidade obtido %v", err)
+	}
+
+	c.Idade.Set(40)
+	c.Email.Set("daniel@gmail.com")
+
+	if err := c.Append(testContext, testDatabase); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidationRequired(t *testing.T) {
+	c := Use[Cliente]()
+	defer c.Close()
+
+	err := c.Append(testContext, testDatabase)
+
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("esperado ValidationErrors obtido %v", err)
+	}
+	if len(verrs) != 1 || verrs[0].Rule != RuleRequired {
+		t.Fatalf("esperado somente a regra required obtido %v", verrs)
+	}
+}
@@ -2,12 +2,17 @@ package rdd
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
-	"time"
 
+	"github.com/dopsilva/rdd/builder"
+	"github.com/dopsilva/rdd/engine"
 	"github.com/dopsilva/rdd/field"
 	"github.com/dopsilva/rdd/schema"
 )
@@ -22,6 +27,8 @@ type Workarea[T any] interface {
 	Replace(ctx context.Context, db Database) error
 	// Remove realiza um delete no banco de dados
 	Remove(ctx context.Context, db Database) error
+	// SoftRemove marca o registro como removido através da coluna rdd-soft-delete
+	SoftRemove(ctx context.Context, db Database) error
 
 	Changed() bool
 	Load(src any) error
@@ -30,12 +37,52 @@ type Workarea[T any] interface {
 
 	GetFieldsAddr(columns []string) []any
 
-	Seek(db Database) error
-	SeekUnique(db Database) error
+	// Seek carrega a entidade do banco de dados através dos valores da primary key
+	Seek(ctx context.Context, db Database, options ...LockOption) error
+	// SeekUnique carrega a entidade do banco de dados através dos valores da unique key
+	SeekUnique(ctx context.Context, db Database, options ...LockOption) error
 
 	Close()
 
 	Triggable
+
+	instance() *workarea[T]
+	committed()
+	retain()
+	release()
+}
+
+// parseDefaultTags lê as expressões por engine (rdd-default-sqlite, ...) e valida o rdd-default
+func parseDefaultTags(tag reflect.StructTag, f *schema.Field) {
+	for _, e := range []DatabaseEngine{SQLite, Cockroach, SQLServer} {
+		if tv, ok := tag.Lookup("rdd-default-" + e.String()); ok {
+			if f.Defaults == nil {
+				f.Defaults = make(map[engine.Engine]string)
+			}
+			f.Defaults[engine.Engine(e)] = tv
+		}
+	}
+
+	if f.Default == "" {
+		return
+	}
+	if f.Generated != "" {
+		panic(fmt.Errorf("workarea: rdd-default and rdd-generated are exclusive on %s", f.Name))
+	}
+
+	d, err := schema.ParseDefault(f.Default)
+	if err != nil {
+		panic(fmt.Errorf("workarea: invalid rdd-default on %s: %w", f.Name, err))
+	}
+	if !d.IsFunction() && d.Literal == nil && !f.Nullable {
+		panic(fmt.Errorf("workarea: invalid rdd-default on %s: null default on a not null column", f.Name))
+	}
+}
+
+// SchemaDefiner é implementada pelas entidades que complementam o schema lido das tags,
+// por exemplo com restrições (Checks) ou colunas geradas (Generated)
+type SchemaDefiner interface {
+	DefineSchema(table *schema.Table)
 }
 
 type Triggable interface {
@@ -50,10 +97,19 @@ type Triggable interface {
 }
 
 type workarea[T any] struct {
-	entity *T
-	schema *schema.Table
-	fields map[string]field.FieldInstance
-	lastop Operation
+	entity    *T
+	schema    *schema.Table
+	fields    map[string]field.FieldInstance
+	relations map[string]relationField
+	lastop    Operation
+	persisted bool // o registro existe no banco de dados
+
+	identities *identityMap // identity map em que a entidade está registrada
+	idkey      string
+	refs       int // referências adicionais obtidas do identity map
+
+	retained int  // transações em que a entidade está armazenada
+	closed   bool // Close executado enquanto armazenada em uma transação
 }
 
 type Operation int
@@ -63,8 +119,23 @@ const (
 	Append
 	Replace
 	Delete
+	SoftDelete
 )
 
+func (o Operation) String() string {
+	switch o {
+	case Append:
+		return "append"
+	case Replace:
+		return "replace"
+	case Delete:
+		return "remove"
+	case SoftDelete:
+		return "soft-remove"
+	}
+	return "none"
+}
+
 type EventParameters struct {
 	Context   context.Context
 	Database  Database
@@ -72,35 +143,40 @@ type EventParameters struct {
 }
 
 var (
-	entitiesPool  = make(map[string]*sync.Pool, 0)
-	entitiesMutex = sync.Mutex{}
+	entitiesPool  = make(map[reflect.Type]*sync.Pool, 0)
+	entitiesMutex = sync.RWMutex{}
 )
 
 // Use faz o uso da entidade. Importante que após o uso, a entidade seja fechada com Close.
 func Use[T any]() *T {
 	var e *T
 
-	en := reflect.TypeOf(e).Elem().Name()
+	en := reflect.TypeOf(e).Elem()
+	entitiesMutex.RLock()
 	p, ok := entitiesPool[en]
+	entitiesMutex.RUnlock()
+
 	if !ok {
 		entitiesMutex.Lock()
 
-		p = &sync.Pool{
-			New: func() any {
-				// instancia a entidade
-				i := new(T)
+		if p, ok = entitiesPool[en]; !ok {
+			p = &sync.Pool{
+				New: func() any {
+					// instancia a entidade
+					i := new(T)
 
-				// instancia a workarea
-				w := newWorkarea[T](i)
+					// instancia a workarea
+					w := newWorkarea[T](i)
 
-				// define a entidade a workarea
-				// TODO: verificar se o field Workarea existe
-				reflect.ValueOf(i).Elem().FieldByName("Workarea").Set(reflect.ValueOf(w))
+					// define a entidade a workarea
+					// TODO: verificar se o field Workarea existe
+					reflect.ValueOf(i).Elem().FieldByName("Workarea").Set(reflect.ValueOf(w))
 
-				return i
-			},
+					return i
+				},
+			}
+			entitiesPool[en] = p
 		}
-		entitiesPool[en] = p
 
 		entitiesMutex.Unlock()
 	}
@@ -110,24 +186,44 @@ func Use[T any]() *T {
 
 // Entity retorna o nome da entidade (estrutura em go)
 func (w *workarea[T]) Close() {
+	// a entidade ainda é referenciada através do identity map
+	if w.refs > 0 {
+		w.refs--
+		return
+	}
+	// a entidade retorna ao pool após o Commit ou Rollback da transação
+	if w.retained > 0 {
+		w.closed = true
+		return
+	}
+	if w.identities != nil {
+		w.identities.remove(w.idkey, any(w.entity))
+		w.identities, w.idkey = nil, ""
+	}
+
 	// reseta os valores
 	w.Reset()
 	// armazena no pool
-	entitiesPool[w.Entity()].Put(w.entity)
+	entitiesMutex.RLock()
+	p := entitiesPool[w.entityType()]
+	entitiesMutex.RUnlock()
+
+	p.Put(w.entity)
 }
 
 func newWorkarea[T any](entity *T) Workarea[T] {
 	schemaCached := true
 
 	w := &workarea[T]{
-		entity: entity,
-		fields: make(map[string]field.FieldInstance, 0),
+		entity:    entity,
+		fields:    make(map[string]field.FieldInstance, 0),
+		relations: make(map[string]relationField, 0),
 	}
 
 	rv := reflect.ValueOf(entity).Elem()
 	rt := reflect.TypeOf(entity).Elem()
 
-	if v, ok := registeredSchemas[rt.Name()]; ok {
+	if v, ok := registeredSchemas[rt]; ok {
 		w.schema = v
 	} else {
 		w.schema = &schema.Table{}
@@ -147,13 +243,18 @@ func newWorkarea[T any](entity *T) Workarea[T] {
 					panic(errors.New("workarea: rdd-table not defined"))
 				}
 			}
+		case relationField:
+			if !schemaCached {
+				w.schema.Relations = append(w.schema.Relations, parseRelationTags(rt.Name(), rt.Field(i).Name, rt.Field(i).Tag, v))
+			}
+			w.relations[rt.Field(i).Name] = v
 		default:
 			if ti, ok := v.(field.Typed); ok {
 				columnName, ok := rt.Field(i).Tag.Lookup("rdd-column")
 				if ok {
 					// se não está cacheado o schema, lemos as informações das tags
 					if !schemaCached {
-						var pk, uk, auto, nullable bool
+						var pk, uk, auto, nullable, sensitive bool
 						var def string
 
 						if tv, ok := rt.Field(i).Tag.Lookup("rdd-primary-key"); ok {
@@ -168,27 +269,58 @@ func newWorkarea[T any](entity *T) Workarea[T] {
 						if tv, ok := rt.Field(i).Tag.Lookup("rdd-nullable"); ok {
 							nullable, _ = strconv.ParseBool(tv)
 						} else {
-							nullable = false
+							// campos que aceitam nulo são nullable por padrão
+							_, nullable = v.(field.Optional)
 						}
 						if tv, ok := rt.Field(i).Tag.Lookup("rdd-default"); ok {
 							def = tv
 						}
+						if tv, ok := rt.Field(i).Tag.Lookup("rdd-sensitive"); ok {
+							sensitive, _ = strconv.ParseBool(tv)
+						}
 
-						w.schema.Fields[columnName] = schema.Field{
+						sf := schema.Field{
 							Name:          columnName,
 							PrimaryKey:    pk,
 							UniqueKey:     uk,
 							AutoGenerated: auto,
 							Nullable:      nullable,
 							Default:       def,
-							FieldType:     ti.Type().Name(),
+							FieldType:     field.TypeName(ti.Type()),
+							Sensitive:     sensitive,
+						}
+
+						if tv, ok := rt.Field(i).Tag.Lookup("rdd-check"); ok {
+							sf.Check = tv
 						}
+						if tv, ok := rt.Field(i).Tag.Lookup("rdd-generated"); ok {
+							sf.Generated = tv
+						}
+						if tv, ok := rt.Field(i).Tag.Lookup("rdd-soft-delete"); ok {
+							v, err := strconv.ParseBool(tv)
+							if err != nil {
+								panic(fmt.Errorf("workarea: invalid rdd-soft-delete on %s: %w", columnName, err))
+							}
+							sf.SoftDelete = v
+						}
+
+						// lê e valida os valores padrão
+						parseDefaultTags(rt.Field(i).Tag, &sf)
+
+						// lê as regras de validação
+						parseValidationTags(rt.Field(i).Tag, &sf)
+
+						w.schema.Fields[columnName] = sf
+					}
+
+					if b, ok := v.(field.Bindable); ok {
+						b.Bind(w.schema.Fields[columnName])
 					}
 
 					fi := field.FieldInstance{
 						Schema: w.schema.Fields[columnName],
 						Addr:   f.Interface(),
-						Type:   ti.Type().Name(),
+						Type:   field.TypeName(ti.Type()),
 					}
 
 					// armazena a instância do campo
@@ -208,14 +340,45 @@ func newWorkarea[T any](entity *T) Workarea[T] {
 						}
 						w.schema.ForeignKeys = append(w.schema.ForeignKeys, schema.ForeignKey{Fields: []string{fkf}, Reference: fkr})
 					}
+					if tv, ok := rt.Field(i).Tag.Lookup("rdd-check"); ok {
+						cn, ok := rt.Field(i).Tag.Lookup("rdd-check-name")
+						if !ok {
+							cn = "ck_" + w.schema.Name + "_" + strings.ToLower(rt.Field(i).Name)
+						}
+						w.schema.Checks = append(w.schema.Checks, schema.Check{Name: cn, Expression: tv})
+					}
 				}
 			}
 		}
 	}
 
+	// se não está cacheado o schema, a entidade pode complementá-lo
+	if !schemaCached {
+		if d, ok := any(entity).(SchemaDefiner); ok {
+			d.DefineSchema(w.schema)
+
+			// atualiza os campos com o schema complementado
+			for k, fi := range w.fields {
+				fi.Schema = w.schema.Fields[k]
+				if b, ok := fi.Addr.(field.Bindable); ok {
+					b.Bind(fi.Schema)
+				}
+				w.fields[k] = fi
+			}
+		}
+	}
+
+	// associa a entidade aos relacionamentos que dependem dela
+	for k, v := range w.relations {
+		if b, ok := v.(relationBinder); ok {
+			rel, _ := w.schema.Relation(k)
+			b.bind(w, w.schema, rel)
+		}
+	}
+
 	// se não está cacheado o schema, colocamos no cache
 	if !schemaCached {
-		registeredSchemas[rt.Name()] = w.schema
+		registeredSchemas[rt] = w.schema
 	}
 
 	return w
@@ -231,6 +394,11 @@ func (w *workarea[T]) Entity() string {
 	return reflect.TypeOf(w.entity).Elem().Name()
 }
 
+// entityType retorna o tipo da entidade
+func (w *workarea[T]) entityType() reflect.Type {
+	return reflect.TypeOf(w.entity).Elem()
+}
+
 // GetFieldsAddr retorna a lista de endereços dos campos através do seu nome de coluna
 func (w *workarea[T]) GetFieldsAddr(columns []string) []any {
 	r := make([]any, 0)
@@ -247,7 +415,31 @@ func (w *workarea[T]) GetFieldsAddr(columns []string) []any {
 }
 
 // Append realiza um insert no banco de dados
-func (w *workarea[T]) Append(ctx context.Context, db Database) error {
+func (w *workarea[T]) Append(ctx context.Context, db Database) (err error) {
+	ctx, done := startOperation(ctx, db, w.Entity(), Append.String())
+	defer done(&err)
+
+	if w.hasCascade(schema.CascadeSave) {
+		return runCascade(ctx, db, w.append)
+	}
+	return w.append(ctx, db)
+}
+
+func (w *workarea[T]) append(ctx context.Context, db Database) error {
+	if !w.visit(ctx, w.entity) {
+		return nil
+	}
+
+	// grava as entidades referenciadas
+	if err := w.saveParents(ctx, db); err != nil {
+		return err
+	}
+
+	// valida os campos da entidade
+	if err := w.validate(EventParameters{Context: ctx, Database: db, Operation: Append}); err != nil {
+		return err
+	}
+
 	// verifica se a entidade implementa o event handler
 	handler, hasHandler := implements[Workarea[T]](w)
 
@@ -257,6 +449,9 @@ func (w *workarea[T]) Append(ctx context.Context, db Database) error {
 			return err
 		}
 	}
+	if err := publish(ctx, BeforeAppend, w.entity); err != nil {
+		return err
+	}
 
 	// executa o insert
 	query, args, ret, err := db.Builder().Insert(*w.schema, w.Fields())
@@ -264,15 +459,13 @@ func (w *workarea[T]) Append(ctx context.Context, db Database) error {
 		return err
 	}
 
-	//fmt.Println(query)
-
 	if len(ret) == 0 {
-		if _, err := db.Exec(query, args...); err != nil {
-			return handler.OnError(err, EventParameters{Context: ctx, Database: db, Operation: Append})
+		if _, err := execContext(ctx, db, w.call(query, args)); err != nil {
+			return onError(handler, err, EventParameters{Context: ctx, Database: db, Operation: Append})
 		}
 	} else {
-		if err := db.QueryRow(query, args...).Scan(ret...); err != nil {
-			return handler.OnError(err, EventParameters{Context: ctx, Database: db, Operation: Append})
+		if err := queryRowContext(ctx, db, w.call(query, args)).Scan(ret...); err != nil {
+			return onError(handler, err, EventParameters{Context: ctx, Database: db, Operation: Append})
 		}
 	}
 
@@ -282,20 +475,51 @@ func (w *workarea[T]) Append(ctx context.Context, db Database) error {
 			return err
 		}
 	}
+	if err := publish(ctx, AfterAppend, w.entity); err != nil {
+		return err
+	}
 
 	w.lastop = Append
+	w.persisted = true
 
 	if !db.WithinTransaction() {
 		w.Freeze()
+		invalidateCache(w.entityType())
+		publishCommitted(ctx, w.entity)
 	} else {
-		db.StoreWorkarea(w)
+		db.StoreWorkarea(w.store())
 	}
 
-	return nil
+	// grava as entidades relacionadas
+	return w.saveChildren(ctx, db)
 }
 
 // Replace realiza um update no banco de dados
-func (w *workarea[T]) Replace(ctx context.Context, db Database) error {
+func (w *workarea[T]) Replace(ctx context.Context, db Database) (err error) {
+	ctx, done := startOperation(ctx, db, w.Entity(), Replace.String())
+	defer done(&err)
+
+	if w.hasCascade(schema.CascadeSave) {
+		return runCascade(ctx, db, w.replace)
+	}
+	return w.replace(ctx, db)
+}
+
+func (w *workarea[T]) replace(ctx context.Context, db Database) error {
+	if !w.visit(ctx, w.entity) {
+		return nil
+	}
+
+	// grava as entidades referenciadas
+	if err := w.saveParents(ctx, db); err != nil {
+		return err
+	}
+
+	// valida os campos da entidade
+	if err := w.validate(EventParameters{Context: ctx, Database: db, Operation: Replace}); err != nil {
+		return err
+	}
+
 	// verifica se implementa o event handler
 	handler, hasHandler := implements[Workarea[T]](w)
 
@@ -305,6 +529,9 @@ func (w *workarea[T]) Replace(ctx context.Context, db Database) error {
 			return err
 		}
 	}
+	if err := publish(ctx, BeforeReplace, w.entity); err != nil {
+		return err
+	}
 
 	// executa o update
 	query, args, ret, err := db.Builder().Update(*w.schema, w.Fields())
@@ -312,15 +539,13 @@ func (w *workarea[T]) Replace(ctx context.Context, db Database) error {
 		return err
 	}
 
-	//fmt.Println(query)
-
 	if len(ret) == 0 {
-		if _, err := db.Exec(query, args...); err != nil {
-			return handler.OnError(err, EventParameters{Context: ctx, Database: db, Operation: Append})
+		if _, err := execContext(ctx, db, w.call(query, args)); err != nil {
+			return onError(handler, err, EventParameters{Context: ctx, Database: db, Operation: Replace})
 		}
 	} else {
-		if err := db.QueryRow(query, args...).Scan(ret...); err != nil {
-			return handler.OnError(err, EventParameters{Context: ctx, Database: db, Operation: Append})
+		if err := queryRowContext(ctx, db, w.call(query, args)).Scan(ret...); err != nil {
+			return onError(handler, err, EventParameters{Context: ctx, Database: db, Operation: Replace})
 		}
 	}
 
@@ -330,20 +555,42 @@ func (w *workarea[T]) Replace(ctx context.Context, db Database) error {
 			return err
 		}
 	}
+	if err := publish(ctx, AfterReplace, w.entity); err != nil {
+		return err
+	}
 
 	w.lastop = Replace
+	w.persisted = true
 
 	if !db.WithinTransaction() {
 		w.Freeze()
+		invalidateCache(w.entityType())
+		publishCommitted(ctx, w.entity)
 	} else {
-		db.StoreWorkarea(w)
+		db.StoreWorkarea(w.store())
 	}
 
-	return nil
+	// grava as entidades relacionadas
+	return w.saveChildren(ctx, db)
 }
 
-// Remove realiza um delete no banco de dados
-func (w *workarea[T]) Remove(ctx context.Context, db Database) error {
+// Remove realiza um delete no banco de dados. As entidades dos relacionamentos com
+// rdd-cascade remove ou soft-delete são removidas antes, na mesma transação.
+func (w *workarea[T]) Remove(ctx context.Context, db Database) (err error) {
+	ctx, done := startOperation(ctx, db, w.Entity(), Delete.String())
+	defer done(&err)
+
+	if w.hasCascade(schema.CascadeRemove, schema.CascadeSoftDelete) {
+		return runCascade(ctx, db, w.remove)
+	}
+	return w.remove(ctx, db)
+}
+
+func (w *workarea[T]) remove(ctx context.Context, db Database) error {
+	if !w.visit(ctx, w.identity()) {
+		return nil
+	}
+
 	// verifica se implementa o event handler
 	handler, hasHandler := implements[Workarea[T]](w)
 
@@ -353,14 +600,20 @@ func (w *workarea[T]) Remove(ctx context.Context, db Database) error {
 			return err
 		}
 	}
+	if err := publish(ctx, BeforeRemove, w.entity); err != nil {
+		return err
+	}
+
+	// remove as entidades relacionadas
+	if err := w.removeChildren(ctx, db); err != nil {
+		return err
+	}
 
 	// executa o update
 	query, args := db.Builder().Delete(*w.schema, w.Fields())
 
-	//fmt.Println(query)
-
-	if _, err := db.Exec(query, args...); err != nil {
-		return handler.OnError(err, EventParameters{Context: ctx, Database: db, Operation: Append})
+	if _, err := execContext(ctx, db, w.call(query, args)); err != nil {
+		return onError(handler, err, EventParameters{Context: ctx, Database: db, Operation: Delete})
 	}
 
 	// executa o event handler
@@ -369,18 +622,54 @@ func (w *workarea[T]) Remove(ctx context.Context, db Database) error {
 			return err
 		}
 	}
+	if err := publish(ctx, AfterRemove, w.entity); err != nil {
+		return err
+	}
 
 	w.lastop = Delete
+	w.persisted = false
 
 	if !db.WithinTransaction() {
 		w.Freeze()
+		invalidateCache(w.entityType())
+		publishCommitted(ctx, w.entity)
 	} else {
-		db.StoreWorkarea(w)
+		db.StoreWorkarea(w.store())
 	}
 
 	return nil
 }
 
+// SoftRemove marca o registro como removido através da coluna rdd-soft-delete e realiza
+// um update no banco de dados, executando os eventos do Replace. As entidades dos
+// relacionamentos com rdd-cascade remove ou soft-delete são removidas na mesma transação.
+func (w *workarea[T]) SoftRemove(ctx context.Context, db Database) (err error) {
+	ctx, done := startOperation(ctx, db, w.Entity(), SoftDelete.String())
+	defer done(&err)
+
+	if w.hasCascade(schema.CascadeRemove, schema.CascadeSoftDelete, schema.CascadeSave) {
+		return runCascade(ctx, db, w.softRemove)
+	}
+	return w.softRemove(ctx, db)
+}
+
+func (w *workarea[T]) softRemove(ctx context.Context, db Database) error {
+	if !w.visit(ctx, w.identity()) {
+		return nil
+	}
+
+	if err := w.markRemoved(); err != nil {
+		return err
+	}
+
+	// remove as entidades relacionadas
+	if err := w.removeChildren(ctx, db); err != nil {
+		return err
+	}
+
+	return w.replace(ctx, db)
+}
+
 // Changed verifica se houve alguma alteração nos campos da workarea
 func (w *workarea[T]) Changed() bool {
 	for _, v := range w.fields {
@@ -405,11 +694,15 @@ func (w *workarea[T]) Freeze() {
 // Reset zera as informações da workarea.
 func (w *workarea[T]) Reset() {
 	w.lastop = None
+	w.persisted = false
 	for _, v := range w.fields {
 		if f, ok := v.Addr.(field.Resetable); ok {
 			f.Reset()
 		}
 	}
+	for _, r := range w.relations {
+		r.clear()
+	}
 }
 
 // Load carrega uma estrutura para a workarea
@@ -422,7 +715,9 @@ func (w *workarea[T]) Load(src any) error {
 
 		if columnName, ok := rt.Field(i).Tag.Lookup("rdd-column"); ok {
 			if field, ok := w.fields[columnName]; ok {
-				w.setField(field, sv)
+				if err := w.setField(field, sv); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -430,19 +725,11 @@ func (w *workarea[T]) Load(src any) error {
 	return nil
 }
 
-func (w *workarea[T]) setField(fi field.FieldInstance, sv any) {
-	switch v := sv.(type) {
-	case string:
-		(fi.Addr.(*field.Field[string])).Set(v)
-	case int64:
-		(fi.Addr.(*field.Field[int64])).Set(v)
-	case float64:
-		(fi.Addr.(*field.Field[float64])).Set(v)
-	case bool:
-		(fi.Addr.(*field.Field[bool])).Set(v)
-	case time.Time:
-		(fi.Addr.(*field.Field[time.Time])).Set(v)
+func (w *workarea[T]) setField(fi field.FieldInstance, sv any) error {
+	if f, ok := fi.Addr.(field.Assignable); ok {
+		return f.Assign(sv)
 	}
+	return fmt.Errorf("workarea: field %s does not accept %T", fi.Schema.Name, sv)
 }
 
 // BeforeAppend é executado antes de adicionar o registro no banco de dados
@@ -466,9 +753,20 @@ func (w *workarea[T]) AfterRemove(params EventParameters) error { return nil }
 // AfterCommit é executado depois de confirmar a transação no banco de dados
 func (w *workarea[T]) AfterCommit(params EventParameters) error { return nil }
 
-// OnError
+// OnError é executado quando o banco de dados retorna erro no Append, Replace ou Remove.
+// O erro retornado substitui o erro original; retornando nil, o erro original é mantido.
 func (w *workarea[T]) OnError(err error, params EventParameters) error { return nil }
 
+// onError executa o OnError da entidade, mantendo o erro original quando ele retorna nil
+func onError(handler Triggable, err error, params EventParameters) error {
+	if handler != nil {
+		if herr := handler.OnError(err, params); herr != nil {
+			return herr
+		}
+	}
+	return err
+}
+
 func implements[I, T any](w *workarea[T]) (I, bool) {
 	c, ok := any(w.entity).(I)
 	return c, ok
@@ -486,5 +784,167 @@ func (w *workarea[T]) Fields() []field.FieldInstance {
 	return fields
 }
 
-func (w *workarea[T]) Seek(db Database) error       { return nil }
-func (w *workarea[T]) SeekUnique(db Database) error { return nil }
+// store retorna a entidade armazenada na transação. A entidade é armazenada no lugar da
+// workarea para que o AfterCommit implementado pela entidade seja executado.
+func (w *workarea[T]) store() field.Freezable {
+	return any(w.entity).(field.Freezable)
+}
+
+func (w *workarea[T]) instance() *workarea[T] {
+	return w
+}
+
+// retain marca a entidade como armazenada em uma transação
+func (w *workarea[T]) retain() {
+	w.retained++
+}
+
+// release libera a entidade armazenada na transação, devolvendo-a ao pool se o Close
+// já foi executado
+func (w *workarea[T]) release() {
+	if w.retained > 0 {
+		w.retained--
+	}
+	if w.retained == 0 && w.closed {
+		w.closed = false
+		w.Close()
+	}
+}
+
+func (w *workarea[T]) relationKey(column string) any {
+	return relationKeyOf(w.entity, column)
+}
+
+// call cria a chamada ao banco de dados com as informações da entidade
+func (w *workarea[T]) call(q string, args []any) *Call {
+	return &Call{Query: q, Args: args, Entity: w.Entity(), Schema: w.schema}
+}
+
+// Seek carrega a entidade do banco de dados através dos valores da primary key.
+//
+// O Seek carrega os valores na própria entidade. Com o identity map habilitado, se o registro
+// já possui uma instância na transação, os valores desta instância são copiados para a
+// entidade sem consultar o banco de dados (leitura através de cópia): as duas instâncias não
+// são compartilhadas e as alterações em uma não são vistas na outra. Para obter a instância
+// compartilhada utilize o Select. Se o registro não possui instância, a entidade é registrada.
+//
+// Com o cache habilitado (EnableCache), a entidade é carregada do cache. Com as opções de
+// bloqueio (ForUpdate, ForShare, ...) o registro é sempre lido do banco de dados.
+func (w *workarea[T]) Seek(ctx context.Context, db Database, options ...LockOption) error {
+	lock, err := lockOf(db, options)
+	if err != nil {
+		return err
+	}
+	if lock.Mode != builder.LockNone {
+		return w.seek(ctx, db, true, lock)
+	}
+
+	if m := identitiesOf(db); m != nil {
+		if key, ok := w.identityKey(); ok {
+			if v, ok := m.load(key); ok && v != any(w.entity) {
+				return w.copyFrom(v.(*T))
+			}
+		}
+	}
+	if w.fromCache(db, true) {
+		return nil
+	}
+	return w.seek(ctx, db, true, lock)
+}
+
+// SeekUnique carrega a entidade do banco de dados através dos valores da unique key.
+// Assim como no Seek, os valores da instância do identity map são copiados para a entidade.
+func (w *workarea[T]) SeekUnique(ctx context.Context, db Database, options ...LockOption) error {
+	lock, err := lockOf(db, options)
+	if err != nil {
+		return err
+	}
+	if lock.Mode == builder.LockNone && w.fromCache(db, false) {
+		return nil
+	}
+	return w.seek(ctx, db, false, lock)
+}
+
+func (w *workarea[T]) seek(ctx context.Context, db Database, primary bool, lock builder.Lock) error {
+	columns := make([]string, 0, len(w.fields))
+	for k := range w.fields {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+
+	where := make([]builder.Condition, 0)
+	for _, c := range columns {
+		fi := w.fields[c]
+		if (primary && fi.Schema.PrimaryKey) || (!primary && fi.Schema.UniqueKey) {
+			v, err := fieldValue(fi)
+			if err != nil {
+				return err
+			}
+			where = append(where, builder.Condition{Column: c, Operator: "=", Values: []any{sensitiveValue(fi, v)}})
+		}
+	}
+	if len(where) == 0 {
+		if primary {
+			return fmt.Errorf("rdd: %s has no primary key", w.Entity())
+		}
+		return fmt.Errorf("rdd: %s has no unique key", w.Entity())
+	}
+
+	q, args, err := db.Builder().Select(*w.schema, &builder.SelectOptions{Columns: columns, Where: where, Lock: lock})
+	if err != nil {
+		return err
+	}
+
+	generation := cacheGeneration(w.entityType())
+	if err := queryRowContext(ctx, db, w.call(q, args)).Scan(w.GetFieldsAddr(columns)...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	w.persisted = true
+	w.Freeze()
+	w.toCache(db, generation)
+
+	// registra a entidade no identity map ou copia os valores da instância já existente
+	if m := identitiesOf(db); m != nil {
+		if key, ok := w.identityKey(); ok {
+			if v, ok := m.load(key); ok && v != any(w.entity) {
+				return w.copyFrom(v.(*T))
+			}
+			w.register(m, key)
+		}
+	}
+
+	return nil
+}
+
+// copyFrom copia os valores de outra instância da entidade
+func (w *workarea[T]) copyFrom(src *T) error {
+	sw := any(src).(Workarea[T]).instance()
+	for k, fi := range sw.fields {
+		v, err := fieldValue(fi)
+		if err != nil {
+			return err
+		}
+		if err := w.setField(w.fields[k], v); err != nil {
+			return err
+		}
+	}
+	w.persisted = sw.persisted
+	w.Freeze()
+	return nil
+}
+
+// identityKey identifica a entidade no identity map pela tabela e primary key
+func (w *workarea[T]) identityKey() (string, bool) {
+	key, ok := w.identity().(string)
+	return key, ok
+}
+
+// register registra a entidade no identity map
+func (w *workarea[T]) register(m *identityMap, key string) {
+	m.store(key, any(w.entity))
+	w.identities, w.idkey = m, key
+}
@@ -280,3 +280,52 @@ func TestSelect(t *testing.T) {
 	}
 
 }
+
+func TestSelectConversion(t *testing.T) {
+	defer truncateTable(testDatabase, "usuarios")
+
+	if _, err := testDatabase.Exec("insert into usuarios (email, nome, incluido_em) values ($1, $2, current_timestamp)", "dopslv@gmail.com", "Daniel"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Select[Usuario](testDatabase, "select id, email, nome, incluido_em, incluido_por from usuarios")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Close()
+
+	if res.Len() != 1 {
+		t.Fatalf("esperado 1 obtido %d", res.Len())
+	}
+	if res[0].IncluidoEm.Get().IsZero() {
+		t.Fatal("esperado a data de inclusão")
+	}
+	if res[0].IncluidoPor.Get().Valid {
+		t.Fatal("esperado incluido_por nulo")
+	}
+}
+
+func TestEntitySameName(t *testing.T) {
+	moedas := schemaOf[Moeda]()
+
+	// entidade com o nome de outra entidade, como em pacotes diferentes
+	type Moeda struct {
+		Workarea[Moeda] `rdd-table:"moedas_locais"`
+
+		ID field.Field[int64] `rdd-column:"id" rdd-primary-key:"true"`
+	}
+	Register[Moeda]()
+	t.Cleanup(func() {
+		delete(registeredSchemas, entityType[Moeda]())
+	})
+
+	m := Use[Moeda]()
+	defer m.Close()
+
+	if m.Schema().Name != "moedas_locais" || schemaOf[Moeda]().Name != "moedas_locais" {
+		t.Fatalf("esperado o schema da entidade local obtido %s", m.Schema().Name)
+	}
+	if moedas.Name != "moedas" || len(moedas.Fields) != 3 {
+		t.Fatalf("schema da entidade alterado %s", moedas.Name)
+	}
+}